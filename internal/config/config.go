@@ -3,8 +3,11 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
 )
 
 const (
@@ -13,38 +16,78 @@ const (
 	DefaultRecordingDir  = "./recordings"
 	DefaultBufferSize    = 65536
 	DefaultPacketTimeout = 5000 // milliseconds
+	DefaultQueueDepth    = 100
+	DefaultSpillSizeMB   = 64
+
+	DefaultCompressionLevel = 6    // gzip default
+	DefaultSyncIntervalMs   = 1000 // milliseconds
 )
 
+// DefaultBackpressurePolicy spills to disk so recordings do not lose
+// packets when the disk is briefly slow
+const DefaultBackpressurePolicy = string(f1telemetry.PolicySpill)
+
 // Config holds the telemetry recorder configuration
 type Config struct {
 	// Network settings
 	UDPPort     int    `json:"udp_port"`
 	BindAddress string `json:"bind_address"`
 
+	// Extra listeners as host:port, e.g. a second game instance, an IPv6
+	// address or a multicast group with an optional @interface suffix
+	Endpoints []string `json:"endpoints"`
+
+	// Other apps received packets are re-sent to
+	Forward []ForwardTarget `json:"forward"`
+
 	// Recording settings
-	RecordingDir    string `json:"recording_dir"`
-	AutoCreateDir   bool   `json:"auto_create_dir"`
-	TimestampFormat string `json:"timestamp_format"`
+	RecordingDir    string   `json:"recording_dir"`
+	AutoCreateDir   bool     `json:"auto_create_dir"`
+	TimestampFormat string   `json:"timestamp_format"`
+	RecordingTags   []string `json:"recording_tags"` // Stored in every recording's metadata, e.g. a league name
+
+	// Compression of recorded packets, 0 = off, 1 = fastest to 9 = smallest
+	CompressionLevel int `json:"compression_level"`
+
+	// How often recordings are synced to disk; a crash loses at most this much
+	SyncIntervalMs int `json:"sync_interval_ms"`
 
 	// Buffer settings
 	BufferSize    int `json:"buffer_size"`
 	PacketTimeout int `json:"packet_timeout"`
 
+	// Backpressure settings, used when packets arrive faster than they are recorded
+	BackpressurePolicy string `json:"backpressure_policy"` // drop-newest, drop-oldest, block or spill
+	QueueDepth         int    `json:"queue_depth"`         // Packets buffered in memory
+	SpillDir           string `json:"spill_dir"`           // Empty for the system temporary directory
+	SpillSizeMB        int    `json:"spill_size_mb"`       // Size of the spill ring on disk
+
 	// Playback settings
 	PlaybackSpeed float64 `json:"playback_speed"` // 1.0 = real-time, 2.0 = 2x speed, etc.
 }
 
+// ForwardTarget is a UDP address packets are forwarded to
+type ForwardTarget struct {
+	Address     string `json:"address"`                // host:port
+	PacketTypes []int  `json:"packet_types,omitempty"` // Packet IDs to forward, all if empty
+}
+
 // NewDefaultConfig returns a configuration with F1 25 defaults
 func NewDefaultConfig() *Config {
 	return &Config{
-		UDPPort:         DefaultUDPPort,
-		BindAddress:     DefaultBindAddress,
-		RecordingDir:    DefaultRecordingDir,
-		AutoCreateDir:   true,
-		TimestampFormat: "2006-01-02_15-04-05",
-		BufferSize:      DefaultBufferSize,
-		PacketTimeout:   DefaultPacketTimeout,
-		PlaybackSpeed:   1.0,
+		UDPPort:            DefaultUDPPort,
+		BindAddress:        DefaultBindAddress,
+		RecordingDir:       DefaultRecordingDir,
+		AutoCreateDir:      true,
+		TimestampFormat:    "2006-01-02_15-04-05",
+		BufferSize:         DefaultBufferSize,
+		PacketTimeout:      DefaultPacketTimeout,
+		BackpressurePolicy: DefaultBackpressurePolicy,
+		QueueDepth:         DefaultQueueDepth,
+		SpillSizeMB:        DefaultSpillSizeMB,
+		CompressionLevel:   DefaultCompressionLevel,
+		SyncIntervalMs:     DefaultSyncIntervalMs,
+		PlaybackSpeed:      1.0,
 	}
 }
 
@@ -66,6 +109,31 @@ func Load(path string) (*Config, error) {
 	return cfg, nil
 }
 
+// ReceiverEndpoints returns the bind address and port followed by the
+// extra endpoints. Invalid extra endpoints are skipped; Validate reports them.
+func (c *Config) ReceiverEndpoints() []f1telemetry.Endpoint {
+	endpoints := []f1telemetry.Endpoint{{Address: c.BindAddress, Port: c.UDPPort}}
+	for _, s := range c.Endpoints {
+		if endpoint, err := f1telemetry.ParseEndpoint(s); err == nil {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// ForwardTargets returns the forward targets for the receiver
+func (c *Config) ForwardTargets() []f1telemetry.ForwardTarget {
+	targets := make([]f1telemetry.ForwardTarget, 0, len(c.Forward))
+	for _, target := range c.Forward {
+		t := f1telemetry.ForwardTarget{Address: target.Address}
+		for _, id := range target.PacketTypes {
+			t.PacketTypes = append(t.PacketTypes, f1telemetry.PacketType(id))
+		}
+		targets = append(targets, t)
+	}
+	return targets
+}
+
 // Save saves configuration to a JSON file
 func (c *Config) Save(path string) error {
 	// Ensure directory exists
@@ -92,6 +160,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid UDP port: %d (must be 1-65535)", c.UDPPort)
 	}
 
+	for _, endpoint := range c.Endpoints {
+		if _, err := f1telemetry.ParseEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+
+	for _, target := range c.Forward {
+		if _, _, err := net.SplitHostPort(target.Address); err != nil {
+			return fmt.Errorf("invalid forward target %q: %w", target.Address, err)
+		}
+		for _, id := range target.PacketTypes {
+			if id < 0 || id > int(f1telemetry.PacketLapPositions) {
+				return fmt.Errorf("invalid packet type %d for forward target %q", id, target.Address)
+			}
+		}
+	}
+
 	if c.RecordingDir == "" {
 		return fmt.Errorf("recording directory cannot be empty")
 	}
@@ -100,6 +185,26 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("buffer size too small: %d (minimum 1024)", c.BufferSize)
 	}
 
+	if _, err := f1telemetry.ParseBackpressurePolicy(c.BackpressurePolicy); err != nil {
+		return err
+	}
+
+	if c.QueueDepth < 1 {
+		return fmt.Errorf("invalid queue depth: %d (must be > 0)", c.QueueDepth)
+	}
+
+	if c.SpillSizeMB < 1 {
+		return fmt.Errorf("invalid spill size: %d MB (must be > 0)", c.SpillSizeMB)
+	}
+
+	if c.CompressionLevel < 0 || c.CompressionLevel > 9 {
+		return fmt.Errorf("invalid compression level: %d (must be 0-9)", c.CompressionLevel)
+	}
+
+	if c.SyncIntervalMs < 1 {
+		return fmt.Errorf("invalid sync interval: %d ms (must be > 0)", c.SyncIntervalMs)
+	}
+
 	if c.PlaybackSpeed <= 0 {
 		return fmt.Errorf("invalid playback speed: %f (must be > 0)", c.PlaybackSpeed)
 	}
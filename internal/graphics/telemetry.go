@@ -3,6 +3,7 @@ package graphics
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // TelemetryDisplay holds current telemetry values for display
@@ -17,6 +18,30 @@ type TelemetryDisplay struct {
 	TyreTempAvg      uint8
 	FuelLevel        float32
 	ERSEnergy        float32
+	LapNumber        uint8
+	Position         uint8
+	LapTime          time.Duration
+	LastLapTime      time.Duration
+}
+
+// LinkHealth holds counters showing how well packets arrive over the network
+type LinkHealth struct {
+	Errors     uint64 // Socket read errors
+	Invalid    uint64 // Datagrams that are not telemetry packets
+	Dropped    uint64 // Packets dropped because the recorder fell behind
+	Queued     int    // Packets waiting to be recorded
+	Lost       uint64 // Packets missing from the sequence
+	Duplicates uint64
+	OutOfOrder uint64
+	Rewinds    uint64 // Flashbacks
+	Forwards   []ForwardHealth
+}
+
+// ForwardHealth holds the counters of one forward target
+type ForwardHealth struct {
+	Target  string
+	Packets uint64
+	Errors  uint64
 }
 
 // ShowLiveTelemetry displays real-time telemetry data with bars
@@ -114,3 +139,13 @@ func createBar(value, max, width int, color string) string {
 	
 	return bar
 }
+
+// formatLapTime formats a lap time as m:ss.mmm
+func formatLapTime(d time.Duration) string {
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	ms := d / time.Millisecond
+	return fmt.Sprintf("%d:%02d.%03d", m, s, ms)
+}
@@ -94,7 +94,7 @@ func (td *TViewDisplay) UpdateRecording(
 	telemetry *TelemetryDisplay,
 	packetsRecorded uint64,
 	bytesWritten uint64,
-	errors uint64,
+	link *LinkHealth,
 	elapsed time.Duration,
 ) {
 	td.mu.Lock()
@@ -120,7 +120,14 @@ func (td *TViewDisplay) UpdateRecording(
 
 	// Stats
 	content.WriteString("\n")
+	var errors uint64
+	if link != nil {
+		errors = link.Errors
+	}
 	content.WriteString(td.formatStats(packetsRecorded, bytesWritten, errors, elapsed, "recording"))
+	if link != nil {
+		content.WriteString(td.formatLinkHealth(link))
+	}
 	
 	// Controls
 	content.WriteString("\n[yellow]💡 Press 'q' to stop recording[white]\n")
@@ -171,7 +178,7 @@ func (td *TViewDisplay) UpdatePlayback(
 	}
 	
 	// Controls
-	content.WriteString("\n[yellow]💡 Press 'q' to stop, 'p' to pause/resume[white]\n")
+	content.WriteString("\n[yellow]💡 Press 'q' to stop, 'p' to pause/resume, ←/→ to seek 30s[white]\n")
 
 	td.mainView.SetText(content.String())
 }
@@ -240,6 +247,12 @@ func (td *TViewDisplay) formatTelemetry(t *TelemetryDisplay) string {
 	}
 	content.WriteString(fmt.Sprintf("💨 DRS Status   │ %s\n", drsStatus))
 	
+	// Lap
+	if t.LapNumber > 0 {
+		content.WriteString(fmt.Sprintf("⏱  Lap          │ [white:b:]Lap %d  P%d  %s[white]  [gray](last %s)[white]\n",
+			t.LapNumber, t.Position, formatLapTime(t.LapTime), formatLapTime(t.LastLapTime)))
+	}
+	
 	content.WriteString("────────────────────────────────────────────────────\n")
 
 	return content.String()
@@ -261,6 +274,35 @@ func (td *TViewDisplay) formatStats(packets, bytes, errors uint64, elapsed time.
 	return content.String()
 }
 
+// formatLinkHealth creates the network health display string
+func (td *TViewDisplay) formatLinkHealth(l *LinkHealth) string {
+	counter := func(label string, value uint64) string {
+		color := "green"
+		if value > 0 {
+			color = "red"
+		}
+		return fmt.Sprintf("[cyan]%s: [%s:b:]%d[white]  ", label, color, value)
+	}
+
+	var content strings.Builder
+	content.WriteString("[cyan]📡 Link  ")
+	content.WriteString(counter("Lost", l.Lost))
+	content.WriteString(counter("Dup", l.Duplicates))
+	content.WriteString(counter("Late", l.OutOfOrder))
+	content.WriteString(counter("Dropped", l.Dropped))
+	content.WriteString(fmt.Sprintf("[cyan]Queued: [white:b:]%d[white]  ", l.Queued))
+	content.WriteString(counter("Invalid", l.Invalid))
+	content.WriteString(fmt.Sprintf("[cyan]⏪ Flashbacks: [white:b:]%d[white]\n", l.Rewinds))
+
+	for _, f := range l.Forwards {
+		content.WriteString(fmt.Sprintf("[cyan]↪  %s  Sent: [white:b:]%d[white]  ", f.Target, f.Packets))
+		content.WriteString(counter("Errors", f.Errors))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
 // createColorBar creates a colored progress bar for tview
 func (td *TViewDisplay) createColorBar(value, max, width int, color string) string {
 	if value > max {
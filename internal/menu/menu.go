@@ -2,6 +2,7 @@ package menu
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,11 +16,15 @@ import (
 	"github.com/pefman/golang-telemetry-recorder/internal/playback"
 	"github.com/pefman/golang-telemetry-recorder/internal/recorder"
 	"github.com/pefman/golang-telemetry-recorder/internal/session"
-	"github.com/pefman/golang-telemetry-recorder/internal/telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
 )
 
 const configFile = "config.json"
 
+// seekStep is how far the arrow keys move playback
+const seekStep = 30 * time.Second
+
 var (
 	cfg    *config.Config
 	reader *bufio.Reader
@@ -106,19 +111,31 @@ func recordSession() error {
 	fmt.Println()
 
 	// Create receiver first to detect session info
-	receiverCfg := telemetry.ReceiverConfig{
-		Port:       cfg.UDPPort,
-		Address:    cfg.BindAddress,
+	receiverCfg := f1telemetry.ReceiverConfig{
+		Endpoints:  cfg.ReceiverEndpoints(),
+		Forward:    cfg.ForwardTargets(),
 		BufferSize: cfg.BufferSize,
 		Timeout:    time.Duration(cfg.PacketTimeout) * time.Millisecond,
+		Policy:     f1telemetry.BackpressurePolicy(cfg.BackpressurePolicy),
+		QueueDepth: cfg.QueueDepth,
+		SpillDir:   cfg.SpillDir,
+		SpillSize:  int64(cfg.SpillSizeMB) << 20,
 	}
-	recv := telemetry.NewReceiver(receiverCfg)
+	recv := f1telemetry.NewReceiver(receiverCfg)
 
 	// Start receiver
 	if err := recv.Start(); err != nil {
 		return fmt.Errorf("failed to start receiver: %w", err)
 	}
-	defer recv.Stop()
+	defer func() {
+		// Stop hands on spilled packets; on early returns nothing reads them
+		go func() {
+			for packet := range recv.Packets() {
+				packet.Release()
+			}
+		}()
+		recv.Stop()
+	}()
 
 	// Create a channel for session detection
 	detectionChan := make(chan []byte, 100)
@@ -132,7 +149,7 @@ func recordSession() error {
 	}()
 
 	// Buffer packets and send to detector
-	var bufferedPackets []*telemetry.RecordedPacket
+	var bufferedPackets []*f1telemetry.RecordedPacket
 	timeout := time.After(30 * time.Second)
 	var sessionInfo *session.SessionInfo
 
@@ -184,6 +201,15 @@ collectLoop:
 	if err != nil {
 		return fmt.Errorf("failed to create recorder: %w", err)
 	}
+	metadata := f1tr.Metadata{Tags: cfg.RecordingTags}
+	if sessionInfo != nil && sessionInfo.HasInfo {
+		metadata.Track = sessionInfo.TrackName
+		metadata.SessionType = sessionInfo.SessionType
+		metadata.Weather = sessionInfo.Weather
+	}
+	rec.SetMetadata(metadata)
+	rec.SetCompressionLevel(cfg.CompressionLevel)
+	rec.SetSyncInterval(time.Duration(cfg.SyncIntervalMs) * time.Millisecond)
 
 	// Start recorder
 	if err := rec.Start(); err != nil {
@@ -196,6 +222,7 @@ collectLoop:
 		if err := rec.RecordPacket(packet); err != nil {
 			fmt.Printf("Error recording buffered packet: %v\n", err)
 		}
+		packet.Release()
 	}
 
 	// Initialize tview display
@@ -209,33 +236,27 @@ collectLoop:
 	stopChan := make(chan struct{})
 	userQuit := make(chan struct{})
 	
-	// Track latest telemetry data
-	var latestTelemetry *telemetry.TelemetryData
-	var playerCarIndex uint8
+	// Track the state of every car frame by frame, apart for each game
+	trackers := f1telemetry.NewOriginTrackers()
 	
-	// Get player car index from header
-	if len(bufferedPackets) > 0 {
-		playerCarIndex = bufferedPackets[0].Header.PlayerCarIndex
+	// Record every packet; the display only needs the latest state, so the
+	// trackers drop packets rather than hold up recording
+	pipeline := f1telemetry.NewPipeline()
+	if err := pipeline.Add("recorder", rec, f1telemetry.StageOptions{Buffer: cfg.QueueDepth}); err != nil {
+		return err
 	}
+	if err := pipeline.Add("state", trackers, f1telemetry.StageOptions{Policy: f1telemetry.PolicyDropOldest}); err != nil {
+		return err
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	
-	// Recording goroutine - must keep reading until recv stops
+	// Runs until recv stops, then finishes recording what is buffered
+	pipelineDone := make(chan struct{})
 	go func() {
-		for packet := range recv.Packets() {
-			// Try to parse telemetry data
-			if packet.Header.PacketID == 6 { // Car telemetry packet
-				if td := telemetry.ParseCarTelemetryPacket(packet.Data, playerCarIndex); td != nil {
-					latestTelemetry = telemetry.MergeTelemetryData(latestTelemetry, td)
-				}
-			} else if packet.Header.PacketID == 7 { // Car status packet
-				if td := telemetry.ParseCarStatusPacket(packet.Data, playerCarIndex); td != nil {
-					latestTelemetry = telemetry.MergeTelemetryData(latestTelemetry, td)
-				}
-			}
-			
-			if err := rec.RecordPacket(packet); err != nil {
-				// Can't print errors in tview mode
-			}
-		}
+		pipeline.Run(ctx, recv)
+		close(pipelineDone)
 	}()
 
 	// Stats display goroutine with tview (flicker-free!)
@@ -243,12 +264,14 @@ collectLoop:
 		ticker := time.NewTicker(16 * time.Millisecond) // 60 FPS - ultra smooth!
 		defer ticker.Stop()
 
+		var frame f1telemetry.FrameState
 		for {
 			select {
 			case <-stopChan:
 				return
 			case <-ticker.C:
 				// Update display if we have telemetry data
+				latestTelemetry := playerTelemetry(trackers.First(), &frame)
 				if latestTelemetry == nil {
 					continue
 				}
@@ -266,6 +289,10 @@ collectLoop:
 					FuelLevel:   latestTelemetry.FuelLevel,
 					ERSEnergy:   latestTelemetry.ERSStoreEnergy,
 					DRS:         latestTelemetry.DRS > 0,
+					LapNumber:   latestTelemetry.CurrentLapNum,
+					Position:    latestTelemetry.CarPosition,
+					LapTime:     latestTelemetry.CurrentLapTime,
+					LastLapTime: latestTelemetry.LastLapTime,
 				}
 				
 				// Show recording stats
@@ -275,7 +302,7 @@ collectLoop:
 				
 				display.UpdateRecording(sessionName, telemetryDisplay, 
 					stats.PacketsRecorded, stats.BytesWritten, 
-					recvStats.Errors, elapsed)
+					linkHealth(recvStats), elapsed)
 			}
 		}
 	}()
@@ -299,6 +326,13 @@ collectLoop:
 	// Stop display first to clean up tview
 	display.Stop()
 	
+	// Stop receiving and let the recorder catch up
+	recv.Stop()
+	<-pipelineDone
+	
+	// Write the index and footer before reporting the recording done
+	stopErr := rec.Stop()
+	
 	// Give time for goroutines to finish and terminal to reset
 	time.Sleep(200 * time.Millisecond)
 	
@@ -311,6 +345,19 @@ collectLoop:
 		stats.BytesWritten, duration)
 	
 	fmt.Printf("\n💾 Output file: %s\n", rec.OutputPath())
+	for _, stage := range pipeline.Stats() {
+		if stage.Name == "recorder" && stage.Errors > 0 {
+			fmt.Printf("❌ Failed to record %d packets, last error: %v\n", stage.Errors, stage.LastError)
+		}
+	}
+	if stopErr != nil {
+		fmt.Printf("❌ %v\n", stopErr)
+	}
+
+	for _, snap := range rec.Setups() {
+		fmt.Printf("🔧 Setup from lap %d: wings %d/%d, brake bias %d%%, fuel %.1f kg\n",
+			snap.Lap, snap.Setup.FrontWing, snap.Setup.RearWing, snap.Setup.BrakeBias, snap.Setup.FuelLoad)
+	}
 
 	pressEnterToContinue()
 	return nil
@@ -405,35 +452,24 @@ func playbackSession() error {
 	stopChan := make(chan struct{})
 	userQuit := make(chan struct{})
 	
-	// Track latest telemetry data
-	var latestTelemetry *telemetry.TelemetryData
-	var playerCarIndex uint8 = 0 // Will be updated from first packet
+	// Track the state of every car frame by frame, apart for each game
+	trackers := f1telemetry.NewOriginTrackers()
 	
 	// Process packets from playback for telemetry display
-	go func() {
-		for packet := range player.Packets() {
-			// Update player car index from header
-			if playerCarIndex == 0 {
-				playerCarIndex = packet.Header.PlayerCarIndex
-			}
-			
-			// Try to parse telemetry data
-			if packet.Header.PacketID == 6 { // Car telemetry packet
-				if td := telemetry.ParseCarTelemetryPacket(packet.Data, playerCarIndex); td != nil {
-					latestTelemetry = telemetry.MergeTelemetryData(latestTelemetry, td)
-				}
-			} else if packet.Header.PacketID == 7 { // Car status packet
-				if td := telemetry.ParseCarStatusPacket(packet.Data, playerCarIndex); td != nil {
-					latestTelemetry = telemetry.MergeTelemetryData(latestTelemetry, td)
-				}
-			}
-		}
-	}()
+	pipeline := f1telemetry.NewPipeline()
+	if err := pipeline.Add("state", trackers, f1telemetry.StageOptions{Policy: f1telemetry.PolicyDropOldest}); err != nil {
+		return err
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pipeline.Run(ctx, player)
 	
 	go func() {
 		ticker := time.NewTicker(16 * time.Millisecond) // 60 FPS - ultra smooth!
 		defer ticker.Stop()
 
+		var frame f1telemetry.FrameState
 		for {
 			select {
 			case <-stopChan:
@@ -441,7 +477,7 @@ func playbackSession() error {
 			case <-ticker.C:
 				// Show live telemetry data if available
 				var telemetryDisplay *graphics.TelemetryDisplay
-				if latestTelemetry != nil {
+				if latestTelemetry := playerTelemetry(trackers.First(), &frame); latestTelemetry != nil {
 					telemetryDisplay = &graphics.TelemetryDisplay{
 						Speed:       latestTelemetry.Speed,
 						Throttle:    latestTelemetry.Throttle,
@@ -454,6 +490,10 @@ func playbackSession() error {
 						FuelLevel:   latestTelemetry.FuelLevel,
 						ERSEnergy:   latestTelemetry.ERSStoreEnergy,
 						DRS:         latestTelemetry.DRS > 0,
+						LapNumber:   latestTelemetry.CurrentLapNum,
+						Position:    latestTelemetry.CarPosition,
+						LapTime:     latestTelemetry.CurrentLapTime,
+						LastLapTime: latestTelemetry.LastLapTime,
 					}
 				}
 				
@@ -468,6 +508,17 @@ func playbackSession() error {
 
 	// Handle keyboard input
 	display.HandleInput(func(key tcell.Key, ch rune) {
+		switch key {
+		case tcell.KeyLeft:
+			if player.Seek(player.Stats().RecordingTime.Add(-seekStep)) == nil {
+				trackers.Reset()
+			}
+		case tcell.KeyRight:
+			if player.Seek(player.Stats().RecordingTime.Add(seekStep)) == nil {
+				trackers.Reset()
+			}
+		}
+
 		switch ch {
 		case 'p', 'P':
 			if player.IsPaused() {
@@ -511,6 +562,9 @@ func playbackSession() error {
 	duration := time.Since(stats.StartTime)
 	graphics.ShowCompletionMessage("playback", stats.PacketsPlayed, 
 		stats.BytesSent, duration)
+	if stats.Damaged > 0 {
+		fmt.Printf("⚠️  Skipped %d damaged records\n", stats.Damaged)
+	}
 
 	pressEnterToContinue()
 	return nil
@@ -542,10 +596,18 @@ func listRecordings() {
 			continue
 		}
 		totalSize += info.Size()
+		created := info.ModTime()
+		header, metadata, err := recorder.ReadMetadata(file)
+		if err == nil {
+			created = header.Created
+		}
 		fmt.Printf("  %d. %s\n", i+1, filepath.Base(file))
 		fmt.Printf("     Size: %s | Created: %s\n",
 			formatFileSize(info.Size()),
-			info.ModTime().Format("2006-01-02 15:04:05"))
+			created.Format("2006-01-02 15:04:05"))
+		if metadata != nil {
+			fmt.Printf("     %s\n", describeMetadata(metadata))
+		}
 		fmt.Println()
 	}
 
@@ -566,10 +628,14 @@ func configureSettings() {
 		fmt.Printf("  4. Buffer Size: %d bytes\n", cfg.BufferSize)
 		fmt.Printf("  5. Packet Timeout: %d ms\n", cfg.PacketTimeout)
 		fmt.Printf("  6. Playback Speed: %.1fx\n", cfg.PlaybackSpeed)
+		fmt.Printf("  7. Backpressure Policy: %s\n", cfg.BackpressurePolicy)
+		fmt.Printf("  8. Queue Depth: %d packets\n", cfg.QueueDepth)
+		fmt.Printf("  9. Spill Size: %d MB\n", cfg.SpillSizeMB)
+		fmt.Printf("  10. Compression Level: %s\n", compressionLevel(cfg.CompressionLevel))
 		fmt.Println()
-		fmt.Println("  7. Save Configuration")
-		fmt.Println("  8. Reset to Defaults")
-		fmt.Println("  9. Back to Main Menu")
+		fmt.Println("  11. Save Configuration")
+		fmt.Println("  12. Reset to Defaults")
+		fmt.Println("  13. Back to Main Menu")
 		fmt.Println()
 
 		choice := readInput("Enter your choice: ")
@@ -608,17 +674,45 @@ func configureSettings() {
 				}
 			}
 		case "7":
+			fmt.Println("  drop-newest  drop packets that arrive while the queue is full")
+			fmt.Println("  drop-oldest  drop queued packets to make room for new ones")
+			fmt.Println("  block        stop reading until there is room")
+			fmt.Println("  spill        queue packets on disk until there is room")
+			if val := readInput("Enter backpressure policy: "); val != "" {
+				if _, err := f1telemetry.ParseBackpressurePolicy(val); err == nil {
+					cfg.BackpressurePolicy = val
+				}
+			}
+		case "8":
+			if val := readInput("Enter queue depth (packets): "); val != "" {
+				if depth, err := strconv.Atoi(val); err == nil {
+					cfg.QueueDepth = depth
+				}
+			}
+		case "9":
+			if val := readInput("Enter spill size (MB): "); val != "" {
+				if size, err := strconv.Atoi(val); err == nil {
+					cfg.SpillSizeMB = size
+				}
+			}
+		case "10":
+			if val := readInput("Enter compression level (0 = off, 1 = fastest to 9 = smallest): "); val != "" {
+				if level, err := strconv.Atoi(val); err == nil && level >= 0 && level <= 9 {
+					cfg.CompressionLevel = level
+				}
+			}
+		case "11":
 			if err := cfg.Save(configFile); err != nil {
 				fmt.Printf("Error saving configuration: %v\n", err)
 			} else {
 				fmt.Println("✓ Configuration saved successfully!")
 			}
 			time.Sleep(1 * time.Second)
-		case "8":
+		case "12":
 			cfg = config.NewDefaultConfig()
 			fmt.Println("✓ Configuration reset to defaults!")
 			time.Sleep(1 * time.Second)
-		case "9":
+		case "13":
 			return
 		}
 	}
@@ -634,7 +728,16 @@ func showStatus() {
 	fmt.Printf("Configuration File: %s\n", configFile)
 	fmt.Printf("UDP Port: %d\n", cfg.UDPPort)
 	fmt.Printf("Bind Address: %s\n", cfg.BindAddress)
+	if len(cfg.Endpoints) > 0 {
+		fmt.Printf("Extra Endpoints: %s\n", strings.Join(cfg.Endpoints, ", "))
+	}
+	for _, target := range cfg.Forward {
+		fmt.Printf("Forwarding To: %s\n", target.Address)
+	}
 	fmt.Printf("Recording Directory: %s\n", cfg.RecordingDir)
+	if len(cfg.RecordingTags) > 0 {
+		fmt.Printf("Recording Tags: %s\n", strings.Join(cfg.RecordingTags, ", "))
+	}
 	fmt.Println()
 
 	// Check if recording directory exists
@@ -693,6 +796,37 @@ func listRecordingFiles() ([]string, error) {
 	return files, nil
 }
 
+// describeMetadata returns a one-line summary of a recording's session
+func describeMetadata(m *f1tr.Metadata) string {
+	parts := []string{}
+	for _, part := range []string{m.Track, m.SessionType, m.Weather} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(m.Participants) > 0 {
+		parts = append(parts, fmt.Sprintf("%d drivers", len(m.Participants)))
+	}
+	if m.GameVersion != "" {
+		parts = append(parts, "F1 "+m.GameVersion)
+	}
+	if len(m.Tags) > 0 {
+		parts = append(parts, "Tags: "+strings.Join(m.Tags, ", "))
+	}
+	if len(parts) == 0 {
+		return "No session details"
+	}
+	return strings.Join(parts, " | ")
+}
+
+// compressionLevel describes a compression level setting
+func compressionLevel(level int) string {
+	if level == 0 {
+		return "off"
+	}
+	return strconv.Itoa(level)
+}
+
 func formatFileSize(size int64) string {
 	const unit = 1024
 	if size < unit {
@@ -705,3 +839,36 @@ func formatFileSize(size int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
+
+// playerTelemetry returns the player car values of the last complete
+// frame, or nil if no frame has completed yet
+func playerTelemetry(tracker *f1telemetry.StateTracker, frame *f1telemetry.FrameState) *f1telemetry.TelemetryData {
+	if tracker == nil || !tracker.Latest(frame) {
+		return nil
+	}
+	car := frame.PlayerCar()
+	if car == nil {
+		return nil
+	}
+	return car.TelemetryData()
+}
+
+// linkHealth summarises receiver stats for the recording display
+func linkHealth(stats f1telemetry.ReceiverStats) *graphics.LinkHealth {
+	sequence := stats.Sequence.Total()
+	forwards := make([]graphics.ForwardHealth, len(stats.Forward))
+	for i, f := range stats.Forward {
+		forwards[i] = graphics.ForwardHealth{Target: f.Target, Packets: f.Packets, Errors: f.Errors}
+	}
+	return &graphics.LinkHealth{
+		Errors:     stats.Errors,
+		Invalid:    stats.InvalidPackets,
+		Dropped:    stats.DroppedNewest + stats.DroppedOldest + stats.DroppedSpill,
+		Queued:     stats.QueueDepth + stats.SpillDepth,
+		Lost:       sequence.Missing,
+		Duplicates: sequence.Duplicates,
+		OutOfOrder: sequence.OutOfOrder,
+		Rewinds:    sequence.Rewinds,
+		Forwards:   forwards,
+	}
+}
@@ -1,7 +1,7 @@
 package playback
 
 import (
-	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -9,7 +9,8 @@ import (
 	"sync"
 	"time"
 	
-	"github.com/pefman/golang-telemetry-recorder/internal/telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
 )
 
 // Player handles playback of recorded telemetry data
@@ -25,7 +26,9 @@ type Player struct {
 	running       bool
 	paused        bool
 	stopChan      chan struct{}
-	packets       chan *telemetry.RecordedPacket
+	packets       chan *f1telemetry.RecordedPacket
+	reader        *f1tr.Reader
+	seeked        bool
 }
 
 // PlayerStats holds playback statistics
@@ -35,6 +38,7 @@ type PlayerStats struct {
 	StartTime     time.Time
 	CurrentTime   time.Time
 	RecordingTime time.Time
+	Damaged       uint64 // Damaged records skipped
 }
 
 // NewPlayer creates a new telemetry player
@@ -45,12 +49,12 @@ func NewPlayer(filePath, targetAddress string, targetPort int, speed float64) (*
 		targetPort:    targetPort,
 		speed:         speed,
 		stopChan:      make(chan struct{}),
-		packets:       make(chan *telemetry.RecordedPacket, 100),
+		packets:       make(chan *f1telemetry.RecordedPacket, 100),
 	}, nil
 }
 
 // Packets returns the channel for receiving parsed packets during playback
-func (p *Player) Packets() <-chan *telemetry.RecordedPacket {
+func (p *Player) Packets() <-chan *f1telemetry.RecordedPacket {
 	return p.packets
 }
 
@@ -71,10 +75,12 @@ func (p *Player) Start() error {
 	p.file = file
 
 	// Validate and skip file header
-	if err := p.readFileHeader(); err != nil {
+	reader, err := f1tr.NewReader(p.file)
+	if err != nil {
 		p.file.Close()
 		return fmt.Errorf("invalid recording file: %w", err)
 	}
+	p.reader = reader
 
 	// Setup UDP connection for sending
 	addr := &net.UDPAddr{
@@ -144,6 +150,21 @@ func (p *Player) Resume() {
 	p.paused = false
 }
 
+// Seek moves playback to the first packet at or after t
+func (p *Player) Seek(t time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running {
+		return fmt.Errorf("player not running")
+	}
+	if err := p.reader.SeekTime(t); err != nil {
+		return fmt.Errorf("failed to seek: %w", err)
+	}
+	p.seeked = true
+	return nil
+}
+
 // IsPaused returns whether playback is paused
 func (p *Player) IsPaused() bool {
 	p.mu.Lock()
@@ -194,7 +215,20 @@ func (p *Player) playbackLoop() {
 			}
 
 			// Read next packet
-			timestamp, packetData, err := p.readPacket()
+			p.mu.Lock()
+			record, err := p.reader.Next()
+			if p.seeked {
+				// Don't wait for the time skipped
+				p.seeked = false
+				lastTimestamp = 0
+			}
+			if errors.Is(err, f1tr.ErrChecksum) {
+				// The reader continues after the damage
+				p.stats.Damaged++
+				p.mu.Unlock()
+				continue
+			}
+			p.mu.Unlock()
 			if err != nil {
 				if err == io.EOF {
 					// Reached end of recording
@@ -205,6 +239,8 @@ func (p *Player) playbackLoop() {
 				p.Stop()
 				return
 			}
+			timestamp := record.Timestamp.UnixNano()
+			packetData := record.Data
 
 			// Calculate delay based on timestamp difference
 			if lastTimestamp != 0 {
@@ -223,12 +259,7 @@ func (p *Player) playbackLoop() {
 			
 			// Parse and send packet to channel for telemetry display (only if still running)
 			if p.IsRunning() {
-				if header, err := telemetry.ParseHeader(packetData); err == nil {
-					packet := &telemetry.RecordedPacket{
-						Timestamp: time.Unix(0, timestamp),
-						Data:      packetData,
-						Header:    *header,
-					}
+				if packet, err := record.Packet(); err == nil {
 					select {
 					case p.packets <- packet:
 					default:
@@ -250,61 +281,6 @@ func (p *Player) playbackLoop() {
 	}
 }
 
-// readFileHeader reads and validates the file header
-func (p *Player) readFileHeader() error {
-	// Read magic
-	magic := make([]byte, 4)
-	if _, err := io.ReadFull(p.file, magic); err != nil {
-		return err
-	}
-	if string(magic) != "F1TR" {
-		return fmt.Errorf("invalid magic number")
-	}
-
-	// Read version
-	var version uint16
-	if err := binary.Read(p.file, binary.LittleEndian, &version); err != nil {
-		return err
-	}
-
-	// Read creation timestamp
-	var createdNano int64
-	if err := binary.Read(p.file, binary.LittleEndian, &createdNano); err != nil {
-		return err
-	}
-
-	// Skip reserved space
-	reserved := make([]byte, 32)
-	if _, err := io.ReadFull(p.file, reserved); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// readPacket reads the next packet from the file
-func (p *Player) readPacket() (int64, []byte, error) {
-	// Read timestamp
-	var timestamp int64
-	if err := binary.Read(p.file, binary.LittleEndian, &timestamp); err != nil {
-		return 0, nil, err
-	}
-
-	// Read packet size
-	var packetSize uint32
-	if err := binary.Read(p.file, binary.LittleEndian, &packetSize); err != nil {
-		return 0, nil, err
-	}
-
-	// Read packet data
-	packetData := make([]byte, packetSize)
-	if _, err := io.ReadFull(p.file, packetData); err != nil {
-		return 0, nil, err
-	}
-
-	return timestamp, packetData, nil
-}
-
 // sendPacket sends a packet via UDP
 func (p *Player) sendPacket(data []byte) error {
 	_, err := p.conn.Write(data)
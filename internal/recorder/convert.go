@@ -0,0 +1,157 @@
+package recorder
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
+)
+
+// Encoding is how a recording stores its packets
+type Encoding string
+
+const (
+	// EncodingRaw stores every packet as received
+	EncodingRaw Encoding = "raw"
+
+	// EncodingDelta stores most packets as the bytes that changed since
+	// the previous packet of the same type, for archives
+	EncodingDelta Encoding = "delta"
+)
+
+// ParseEncoding returns the encoding with the given name
+func ParseEncoding(s string) (Encoding, error) {
+	switch e := Encoding(s); e {
+	case EncodingRaw, EncodingDelta:
+		return e, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q (must be raw or delta)", s)
+}
+
+// ConvertOptions selects how a converted recording stores its packets
+type ConvertOptions struct {
+	Encoding         Encoding
+	Level            int // Compression level, 0 for none
+	KeyframeInterval int // Packets between delta keyframes, 0 for the default
+}
+
+// ConvertStats describes a finished conversion
+type ConvertStats struct {
+	Packets    uint64
+	InputSize  int64
+	OutputSize int64
+}
+
+// Convert rewrites the recording at src to dst, storing the packets as set
+// in opts. Packets, timestamps, origins, the creation time and the session
+// metadata are kept exactly. dst is only replaced once it is complete.
+// Damaged recordings, including torn ones, are an error so that no packet
+// is lost without notice; Recover repairs them.
+func Convert(src, dst string, opts ConvertOptions) (ConvertStats, error) {
+	var stats ConvertStats
+
+	in, err := os.Open(src)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer in.Close()
+
+	reader, err := f1tr.NewReader(in)
+	if err != nil {
+		return stats, fmt.Errorf("invalid recording file: %w", err)
+	}
+	metadata, err := reader.Metadata()
+	if err != nil {
+		return stats, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	stats.InputSize, stats.OutputSize, err = replace(in, dst, func(out io.Writer) error {
+		writer, err := f1tr.NewWriterOptions(out, f1tr.WriterOptions{
+			Level:            opts.Level,
+			Delta:            opts.Encoding == EncodingDelta,
+			KeyframeInterval: opts.KeyframeInterval,
+			Created:          reader.Header().Created,
+		})
+		if err != nil {
+			return err
+		}
+		if metadata != nil {
+			if err := writer.WriteMetadata(metadata); err != nil {
+				return err
+			}
+		}
+
+		for {
+			record, err := reader.Next()
+			if err == io.EOF && reader.Torn() {
+				// Archives would silently lose the rest
+				return fmt.Errorf("recording ends in a torn record after %d packets, recover it first", stats.Packets)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read packet %d: %w", stats.Packets+1, err)
+			}
+			packet := f1telemetry.RecordedPacket{
+				Timestamp: record.Timestamp,
+				Data:      record.Data,
+				Origin:    record.Origin,
+			}
+			if err := writer.WritePacket(&packet); err != nil {
+				return err
+			}
+			stats.Packets++
+		}
+		return writer.Close()
+	})
+	return stats, err
+}
+
+// replace writes a recording with write to a temporary file next to dst
+// and renames it to dst once it is complete and synced to disk, so a
+// crash leaves either the old or the new file. It closes in, the
+// recording read from, before the rename so that it may be dst. It
+// returns the sizes of in and the new file.
+func replace(in *os.File, dst string, write func(out io.Writer) error) (int64, int64, error) {
+	out, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+	out.Chmod(0644)
+
+	buffered := bufio.NewWriterSize(out, bufferSize)
+	if err := write(buffered); err != nil {
+		return 0, 0, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return 0, 0, fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("failed to sync output file: %w", err)
+	}
+	var outputSize int64
+	if info, err := out.Stat(); err == nil {
+		outputSize = info.Size()
+	}
+	if err := out.Close(); err != nil {
+		return 0, 0, fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	// Open files can not be replaced on Windows
+	var inputSize int64
+	if info, err := in.Stat(); err == nil {
+		inputSize = info.Size()
+	}
+	in.Close()
+	if err := os.Rename(out.Name(), dst); err != nil {
+		return inputSize, outputSize, fmt.Errorf("failed to replace output file: %w", err)
+	}
+	return inputSize, outputSize, nil
+}
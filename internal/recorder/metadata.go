@@ -0,0 +1,83 @@
+package recorder
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pefman/golang-telemetry-recorder/internal/session"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
+)
+
+// Version is the recorder version stored in recordings
+const Version = "1.0"
+
+// SessionMetadata keeps the metadata of a recording up to date. Feed it
+// packets in recording order with Observe.
+type SessionMetadata struct {
+	metadata f1tr.Metadata
+
+	// Decode buffers reused between packets
+	session      f1telemetry.PacketSessionData
+	participants f1telemetry.PacketParticipantsData
+}
+
+// Observe updates the metadata from the packet header and from Session
+// and Participants packets
+func (m *SessionMetadata) Observe(packet *f1telemetry.RecordedPacket) {
+	h := &packet.Header
+	m.metadata.PacketFormat = h.PacketFormat
+	m.metadata.SessionUID = h.SessionUID
+	m.metadata.PlayerCarIndex = h.PlayerCarIndex
+
+	switch f1telemetry.PacketType(h.PacketID) {
+	case f1telemetry.PacketSession:
+		if err := m.session.UnmarshalBinary(packet.Data); err != nil {
+			return
+		}
+		m.metadata.GameVersion = fmt.Sprintf("%d %d.%d", h.GameYear, h.GameMajorVersion, h.GameMinorVersion)
+		m.metadata.Track = session.TrackName(m.session.TrackID)
+		m.metadata.SessionType = session.SessionTypeName(m.session.SessionType)
+		m.metadata.Weather = session.WeatherName(m.session.Weather)
+	case f1telemetry.PacketParticipants:
+		if err := m.participants.UnmarshalBinary(packet.Data); err != nil {
+			return
+		}
+		n := min(int(m.participants.NumActiveCars), f1telemetry.MaxCars)
+		m.metadata.Participants = m.metadata.Participants[:0]
+		for _, p := range m.participants.Participants[:n] {
+			m.metadata.Participants = append(m.metadata.Participants, f1tr.Participant{
+				Name:        p.NameString(),
+				TeamID:      p.TeamID,
+				RaceNumber:  p.RaceNumber,
+				Nationality: p.Nationality,
+				AI:          p.AIControlled == 1,
+			})
+		}
+	}
+}
+
+// Metadata returns a copy of the metadata
+func (m *SessionMetadata) Metadata() f1tr.Metadata {
+	metadata := m.metadata
+	metadata.Participants = append([]f1tr.Participant(nil), m.metadata.Participants...)
+	metadata.Tags = append([]string(nil), m.metadata.Tags...)
+	return metadata
+}
+
+// ReadMetadata returns the file header and session metadata of a
+// recording. The metadata is nil if the recording has none.
+func ReadMetadata(path string) (f1tr.Header, *f1tr.Metadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return f1tr.Header{}, nil, err
+	}
+	defer file.Close()
+
+	reader, err := f1tr.NewReader(file)
+	if err != nil {
+		return f1tr.Header{}, nil, err
+	}
+	metadata, err := reader.Metadata()
+	return reader.Header(), metadata, err
+}
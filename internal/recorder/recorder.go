@@ -1,25 +1,45 @@
 package recorder
 
 import (
-	"encoding/binary"
+	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/pefman/golang-telemetry-recorder/internal/telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
 )
 
 // Recorder handles recording telemetry data to files
 type Recorder struct {
 	outputPath string
 	file       *os.File
+	buffered   *bufio.Writer
+	writer     *f1tr.Writer
 	mu         sync.Mutex
 	stats      RecorderStats
 	running    bool
+	setups     SetupHistory
+	metadata   SessionMetadata
+	level      int // Compression level, 0 for none
+
+	// Buffered packets are flushed and synced to disk every syncInterval,
+	// so a crash loses at most that much of the recording
+	syncInterval time.Duration
+	syncErr      error
+	stopSync     chan struct{}
+	syncDone     chan struct{}
 }
 
+// DefaultSyncInterval is how often recordings are synced to disk
+const DefaultSyncInterval = time.Second
+
+// bufferSize is the size of the write buffer in front of the file
+const bufferSize = 256 << 10
+
 // RecorderStats holds recording statistics
 type RecorderStats struct {
 	PacketsRecorded uint64
@@ -28,21 +48,6 @@ type RecorderStats struct {
 	SessionName     string
 }
 
-// FileHeader is written at the start of recording files
-type FileHeader struct {
-	Magic       [4]byte   // "F1TR" (F1 Telemetry Recording)
-	Version     uint16    // File format version
-	Created     time.Time // Recording creation time
-	Reserved    [32]byte  // Reserved for future use
-}
-
-// PacketEntry represents a packet in the recording file
-type PacketEntry struct {
-	Timestamp   int64  // Unix nanoseconds
-	PacketSize  uint32 // Size of packet data
-	PacketData  []byte // Raw packet data
-}
-
 // NewRecorder creates a new telemetry recorder
 func NewRecorder(outputDir, sessionName string) (*Recorder, error) {
 	// Create output directory if needed
@@ -56,7 +61,8 @@ func NewRecorder(outputDir, sessionName string) (*Recorder, error) {
 	outputPath := filepath.Join(outputDir, filename)
 
 	return &Recorder{
-		outputPath: outputPath,
+		outputPath:   outputPath,
+		syncInterval: DefaultSyncInterval,
 		stats: RecorderStats{
 			SessionName: sessionName,
 		},
@@ -78,41 +84,148 @@ func (r *Recorder) Start() error {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 
+	// Write file header
+	buffered := bufio.NewWriterSize(file, bufferSize)
+	writer, err := f1tr.NewWriterLevel(buffered, r.level)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	// Describe the session before the first packet
+	r.metadata.metadata.RecorderVersion = Version
+	r.metadata.metadata.Host, _ = os.Hostname()
+	metadata := r.metadata.Metadata()
+	if err := writer.WriteMetadata(&metadata); err != nil {
+		file.Close()
+		return err
+	}
+
 	r.file = file
+	r.buffered = buffered
+	r.writer = writer
 	r.running = true
 	r.stats.StartTime = time.Now()
+	r.syncErr = nil
 
-	// Write file header
-	if err := r.writeFileHeader(); err != nil {
-		r.file.Close()
-		return fmt.Errorf("failed to write file header: %w", err)
+	r.stopSync = make(chan struct{})
+	r.syncDone = make(chan struct{})
+	go r.syncLoop(r.syncInterval, r.stopSync, r.syncDone)
+
+	return nil
+}
+
+// syncLoop syncs the recording to disk every interval until stop is closed
+func (r *Recorder) syncLoop(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			if err := r.sync(); err != nil && r.syncErr == nil {
+				r.syncErr = err
+			}
+			r.mu.Unlock()
+		}
 	}
+}
 
+// sync writes out buffered packets and waits for them to reach the disk.
+// Callers must hold r.mu.
+func (r *Recorder) sync() error {
+	if err := r.buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := r.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync recording: %w", err)
+	}
 	return nil
 }
 
-// Stop stops recording and closes the file
-func (r *Recorder) Stop() error {
+// SetSyncInterval sets how often the recording is synced to disk. Packets
+// recorded since the last sync are lost if the computer crashes. It
+// applies from the next Start.
+func (r *Recorder) SetSyncInterval(interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	r.syncInterval = interval
+}
+
+// SetCompressionLevel sets the gzip level packets are compressed with,
+// from 1 (fastest) to 9 (smallest), or 0 to store them uncompressed. It
+// applies from the next Start.
+func (r *Recorder) SetCompressionLevel(level int) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	r.level = level
+}
 
+// SetMetadata sets the session metadata written when recording starts.
+// Recorded packets keep it up to date and it is written again on Stop.
+func (r *Recorder) SetMetadata(metadata f1tr.Metadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metadata.metadata = metadata
+}
+
+// Metadata returns the session metadata as it stands
+func (r *Recorder) Metadata() f1tr.Metadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metadata.Metadata()
+}
+
+// Stop stops recording and closes the file
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
 	if !r.running {
+		r.mu.Unlock()
 		return nil
 	}
-
 	r.running = false
 
-	if r.file != nil {
-		if err := r.file.Close(); err != nil {
-			return fmt.Errorf("failed to close file: %w", err)
-		}
+	// The sync loop takes the lock, so it is stopped without it
+	close(r.stopSync)
+	r.mu.Unlock()
+	<-r.syncDone
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Write the final metadata, the seek index and the footer
+	metadata := r.metadata.Metadata()
+	if err := r.writer.WriteMetadata(&metadata); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to finish recording: %w", err)
+	}
+	if err := r.writer.Close(); err != nil {
+		r.file.Close()
+		return fmt.Errorf("failed to finish recording: %w", err)
+	}
+	if err := r.sync(); err != nil {
+		r.file.Close()
+		return err
 	}
 
-	return nil
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	// A failed sync may have lost packets the recording seems to have
+	return r.syncErr
 }
 
 // RecordPacket writes a packet to the recording file
-func (r *Recorder) RecordPacket(packet *telemetry.RecordedPacket) error {
+func (r *Recorder) RecordPacket(packet *f1telemetry.RecordedPacket) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -120,31 +233,28 @@ func (r *Recorder) RecordPacket(packet *telemetry.RecordedPacket) error {
 		return fmt.Errorf("recorder not running")
 	}
 
-	// Write timestamp (8 bytes)
-	timestamp := packet.Timestamp.UnixNano()
-	if err := binary.Write(r.file, binary.LittleEndian, timestamp); err != nil {
-		return fmt.Errorf("failed to write timestamp: %w", err)
-	}
-
-	// Write packet size (4 bytes)
-	packetSize := uint32(len(packet.Data))
-	if err := binary.Write(r.file, binary.LittleEndian, packetSize); err != nil {
-		return fmt.Errorf("failed to write packet size: %w", err)
-	}
-
-	// Write packet data
-	n, err := r.file.Write(packet.Data)
+	size := r.writer.Size()
+	err := r.writer.WritePacket(packet)
+	r.stats.BytesWritten += uint64(r.writer.Size() - size)
 	if err != nil {
-		return fmt.Errorf("failed to write packet data: %w", err)
+		return err
 	}
 
+	// Note setup changes for the player car and session details
+	r.setups.Observe(packet)
+	r.metadata.Observe(packet)
+
 	// Update stats
 	r.stats.PacketsRecorded++
-	r.stats.BytesWritten += uint64(n + 12) // data + timestamp + size
 
 	return nil
 }
 
+// HandlePacket makes the recorder a pipeline stage
+func (r *Recorder) HandlePacket(ctx context.Context, packet *f1telemetry.RecordedPacket) error {
+	return r.RecordPacket(packet)
+}
+
 // Stats returns current recorder statistics
 func (r *Recorder) Stats() RecorderStats {
 	r.mu.Lock()
@@ -152,6 +262,20 @@ func (r *Recorder) Stats() RecorderStats {
 	return r.stats
 }
 
+// Setups returns every distinct player car setup seen so far
+func (r *Recorder) Setups() []SetupSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.setups.Snapshots()
+}
+
+// SetupForLap returns the player car setup that was active on the given lap
+func (r *Recorder) SetupForLap(lap uint8) (f1telemetry.CarSetupData, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.setups.SetupForLap(lap)
+}
+
 // IsRunning returns whether the recorder is active
 func (r *Recorder) IsRunning() bool {
 	r.mu.Lock()
@@ -163,35 +287,3 @@ func (r *Recorder) IsRunning() bool {
 func (r *Recorder) OutputPath() string {
 	return r.outputPath
 }
-
-// writeFileHeader writes the file header
-func (r *Recorder) writeFileHeader() error {
-	header := FileHeader{
-		Magic:   [4]byte{'F', '1', 'T', 'R'},
-		Version: 1,
-		Created: time.Now(),
-	}
-
-	// Write magic
-	if _, err := r.file.Write(header.Magic[:]); err != nil {
-		return err
-	}
-
-	// Write version
-	if err := binary.Write(r.file, binary.LittleEndian, header.Version); err != nil {
-		return err
-	}
-
-	// Write creation timestamp
-	createdNano := header.Created.UnixNano()
-	if err := binary.Write(r.file, binary.LittleEndian, createdNano); err != nil {
-		return err
-	}
-
-	// Write reserved space
-	if _, err := r.file.Write(header.Reserved[:]); err != nil {
-		return err
-	}
-
-	return nil
-}
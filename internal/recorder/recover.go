@@ -0,0 +1,59 @@
+package recorder
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
+)
+
+// RecoverOptions selects how a recovered recording stores its packets
+type RecoverOptions struct {
+	Level            int // Compression level, 0 for none
+	KeyframeInterval int // Packets between delta keyframes, 0 for the default
+}
+
+// RecoverStats describes a finished recovery
+type RecoverStats struct {
+	f1tr.RecoverStats
+	InputSize  int64
+	OutputSize int64
+}
+
+// Recover rewrites every intact packet of the damaged recording at src to
+// dst, stored as set in opts. Delta encoded recordings stay delta encoded.
+// dst is only replaced once it is complete, so src may be recovered in
+// place.
+func Recover(src, dst string, opts RecoverOptions) (RecoverStats, error) {
+	var stats RecoverStats
+
+	in, err := os.Open(src)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer in.Close()
+
+	reader, err := f1tr.NewReader(in)
+	if err != nil {
+		return stats, fmt.Errorf("invalid recording file: %w", err)
+	}
+	header := reader.Header()
+	if _, err := in.Seek(0, io.SeekStart); err != nil {
+		return stats, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	stats.InputSize, stats.OutputSize, err = replace(in, dst, func(out io.Writer) error {
+		var err error
+		stats.RecoverStats, err = f1tr.Recover(out, in, f1tr.WriterOptions{
+			Level:            opts.Level,
+			Delta:            header.Flags&f1tr.FlagDelta != 0,
+			KeyframeInterval: opts.KeyframeInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to recover recording: %w", err)
+		}
+		return nil
+	})
+	return stats, err
+}
@@ -0,0 +1,121 @@
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
+)
+
+// SetupSnapshot is a player car setup and the lap it was first seen on
+type SetupSnapshot struct {
+	Lap       uint8
+	Timestamp time.Time
+	Setup     f1telemetry.CarSetupData
+}
+
+// SetupHistory tracks every distinct player car setup seen in a session.
+// Feed it packets in recording order with Observe. A new session starts
+// a new history.
+type SetupHistory struct {
+	sessionUID uint64
+	currentLap uint8
+	snapshots  []SetupSnapshot
+
+	// Decode buffers reused between packets
+	lapData   f1telemetry.LapDataPacket
+	carSetups f1telemetry.PacketCarSetupData
+}
+
+// Observe updates the history from a Lap Data or Car Setups packet.
+// Other packet types are ignored.
+func (h *SetupHistory) Observe(packet *f1telemetry.RecordedPacket) {
+	playerCarIndex := packet.Header.PlayerCarIndex
+	if int(playerCarIndex) >= f1telemetry.MaxCars {
+		return
+	}
+
+	// Laps and setups of another session do not apply; packets sent
+	// outside a session have no UID
+	if uid := packet.Header.SessionUID; uid != 0 && uid != h.sessionUID {
+		h.sessionUID = uid
+		h.currentLap = 0
+		h.snapshots = h.snapshots[:0]
+	}
+
+	switch f1telemetry.PacketType(packet.Header.PacketID) {
+	case f1telemetry.PacketLapData:
+		if err := h.lapData.UnmarshalBinary(packet.Data); err == nil {
+			h.currentLap = h.lapData.LapData[playerCarIndex].CurrentLapNum
+		}
+	case f1telemetry.PacketCarSetups:
+		if err := h.carSetups.UnmarshalBinary(packet.Data); err != nil {
+			return
+		}
+		setup := h.carSetups.CarSetups[playerCarIndex]
+		if setup.IsBlank() {
+			return
+		}
+		if n := len(h.snapshots); n > 0 && h.snapshots[n-1].Setup == setup {
+			return
+		}
+		h.snapshots = append(h.snapshots, SetupSnapshot{
+			Lap:       h.currentLap,
+			Timestamp: packet.Timestamp,
+			Setup:     setup,
+		})
+	}
+}
+
+// Snapshots returns the distinct setups in the order they were seen
+func (h *SetupHistory) Snapshots() []SetupSnapshot {
+	out := make([]SetupSnapshot, len(h.snapshots))
+	copy(out, h.snapshots)
+	return out
+}
+
+// SetupForLap returns the setup that was active on the given lap
+func (h *SetupHistory) SetupForLap(lap uint8) (f1telemetry.CarSetupData, bool) {
+	for i := len(h.snapshots) - 1; i >= 0; i-- {
+		if h.snapshots[i].Lap <= lap {
+			return h.snapshots[i].Setup, true
+		}
+	}
+	return f1telemetry.CarSetupData{}, false
+}
+
+// ReadSetups returns the setup history of the last session in a
+// recording. Damaged parts of the recording are skipped.
+func ReadSetups(path string) (*SetupHistory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := f1tr.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &SetupHistory{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return history, nil
+		}
+		if errors.Is(err, f1tr.ErrChecksum) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		if packet, err := record.Packet(); err == nil {
+			history.Observe(packet)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package session
 import (
 	"fmt"
 	"strings"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
 )
 
 // SessionInfo holds extracted session information
@@ -119,82 +121,57 @@ func ExtractSessionInfo(packets <-chan []byte) *SessionInfo {
 
 // parseSessionPacket extracts info from session packet
 func parseSessionPacket(data []byte, info *SessionInfo) {
-	if len(data) < 100 {
+	pkt, err := f1telemetry.ParseSessionPacket(data)
+	if err != nil {
 		return
 	}
-	
-	// Skip header (29 bytes)
-	offset := 29
-	
-	weather := data[offset]
-	info.Weather = weatherConditions[weather]
-	if info.Weather == "" {
-		info.Weather = "Unknown"
+
+	info.Weather = WeatherName(pkt.Weather)
+	info.SessionType = SessionTypeName(pkt.SessionType)
+	info.TrackName = TrackName(pkt.TrackID)
+
+	// Time of day is sent as minutes since midnight
+	info.TimeOfDay = fmt.Sprintf("%02d:%02d", pkt.TimeOfDay/60%24, pkt.TimeOfDay%60)
+}
+
+// TrackName returns the name of a track ID
+func TrackName(id int8) string {
+	if name, ok := trackNames[id]; ok {
+		return name
 	}
-	
-	offset += 1 // m_weather
-	offset += 1 // m_trackTemperature
-	offset += 1 // m_airTemperature
-	offset += 1 // m_totalLaps
-	offset += 2 // m_trackLength
-	
-	sessionType := data[offset]
-	info.SessionType = sessionTypes[sessionType]
-	if info.SessionType == "" {
-		info.SessionType = "Unknown"
+	return fmt.Sprintf("Track%d", id)
+}
+
+// SessionTypeName returns the name of a session type
+func SessionTypeName(sessionType uint8) string {
+	if name, ok := sessionTypes[sessionType]; ok {
+		return name
 	}
-	offset += 1
-	
-	trackID := int8(data[offset])
-	info.TrackName = trackNames[trackID]
-	if info.TrackName == "" {
-		info.TrackName = fmt.Sprintf("Track%d", trackID)
+	return "Unknown"
+}
+
+// WeatherName returns the name of a weather condition
+func WeatherName(weather uint8) string {
+	if name, ok := weatherConditions[weather]; ok {
+		return name
 	}
-	
-	// Extract time of day (further in the packet)
-	// Skip to timeOfDay offset (check documentation for exact position)
-	// For now, we'll skip this as it's deep in the structure
+	return "Unknown"
 }
 
 // parseParticipantsPacket extracts player name from participants packet
 func parseParticipantsPacket(data []byte, info *SessionInfo) {
-	if len(data) < 30 {
+	pkt, err := f1telemetry.ParseParticipantsPacket(data)
+	if err != nil {
 		return
 	}
-	
-	// Get player car index from header
-	playerCarIndex := data[27]
-	
-	// Skip header (29 bytes)
-	offset := 29
-	
-	// Read numActiveCars
-	if offset >= len(data) {
-		return
-	}
-	offset += 1 // m_numActiveCars
-	
-	// Each ParticipantData is approximately 58 bytes
-	// Calculate offset to player's data
-	participantOffset := offset + (int(playerCarIndex) * 58)
-	
-	if participantOffset+58 > len(data) {
-		return
-	}
-	
-	// Skip to name field (48 bytes into ParticipantData)
-	nameOffset := participantOffset + 48
-	
-	if nameOffset+32 > len(data) {
+
+	playerCarIndex := pkt.Header.PlayerCarIndex
+	if int(playerCarIndex) >= len(pkt.Participants) {
 		return
 	}
-	
-	// Extract name (32 bytes, null-terminated UTF-8)
-	nameBytes := data[nameOffset : nameOffset+32]
-	name := extractNullTerminatedString(nameBytes)
-	
+
 	// Clean up the name
-	name = strings.TrimSpace(name)
+	name := strings.TrimSpace(pkt.Participants[playerCarIndex].NameString())
 	if name == "" {
 		name = "Player"
 	}
@@ -205,16 +182,6 @@ func parseParticipantsPacket(data []byte, info *SessionInfo) {
 	info.PlayerName = name
 }
 
-// extractNullTerminatedString extracts a null-terminated string
-func extractNullTerminatedString(data []byte) string {
-	for i, b := range data {
-		if b == 0 {
-			return string(data[:i])
-		}
-	}
-	return string(data)
-}
-
 // sanitizeForFilename removes characters that aren't safe for filenames
 func sanitizeForFilename(s string) string {
 	// Replace spaces with underscores
@@ -278,6 +245,9 @@ func (si *SessionInfo) String() string {
 	if si.Weather != "" {
 		parts = append(parts, fmt.Sprintf("Weather: %s", si.Weather))
 	}
+	if si.TimeOfDay != "" {
+		parts = append(parts, fmt.Sprintf("Time: %s", si.TimeOfDay))
+	}
 	
 	return strings.Join(parts, " | ")
 }
@@ -1,15 +1,38 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/pefman/golang-telemetry-recorder/internal/config"
 	"github.com/pefman/golang-telemetry-recorder/internal/menu"
+	"github.com/pefman/golang-telemetry-recorder/internal/recorder"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		var command func([]string) error
+		switch os.Args[1] {
+		case "convert":
+			command = convert
+		case "recover":
+			command = recoverRecording
+		case "setups":
+			command = setups
+		}
+		if command != nil {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	fmt.Println("==============================================")
-	fmt.Println("  F1 Telemetry Recorder - v1.0")
+	fmt.Printf("  F1 Telemetry Recorder - v%s\n", recorder.Version)
 	fmt.Println("==============================================")
 	fmt.Println()
 
@@ -18,3 +41,117 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// convert rewrites a recording with another packet encoding
+func convert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	encoding := flags.String("encoding", string(recorder.EncodingDelta), "packet encoding, raw or delta")
+	level := flags.Int("level", config.DefaultCompressionLevel, "compression level, 0 = off, 1 = fastest to 9 = smallest")
+	keyframes := flags.Int("keyframe-interval", f1tr.DefaultKeyframeInterval, "packets between delta keyframes")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s convert [flags] <input.f1tr> <output.f1tr>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	enc, err := recorder.ParseEncoding(*encoding)
+	if err != nil {
+		return err
+	}
+	stats, err := recorder.Convert(flags.Arg(0), flags.Arg(1), recorder.ConvertOptions{
+		Encoding:         enc,
+		Level:            *level,
+		KeyframeInterval: *keyframes,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Converted %d packets to %s: %d -> %d bytes", stats.Packets, flags.Arg(1), stats.InputSize, stats.OutputSize)
+	if stats.InputSize > 0 {
+		fmt.Printf(" (%.0f%%)", float64(stats.OutputSize)/float64(stats.InputSize)*100)
+	}
+	fmt.Println()
+	return nil
+}
+
+// recoverRecording salvages the intact packets of a damaged recording
+func recoverRecording(args []string) error {
+	flags := flag.NewFlagSet("recover", flag.ExitOnError)
+	level := flags.Int("level", config.DefaultCompressionLevel, "compression level, 0 = off, 1 = fastest to 9 = smallest")
+	keyframes := flags.Int("keyframe-interval", f1tr.DefaultKeyframeInterval, "packets between delta keyframes of delta encoded recordings")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s recover [flags] <damaged.f1tr> [output.f1tr]\n", os.Args[0])
+		fmt.Fprintln(flags.Output(), "Without an output the recording is repaired in place.")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	src, dst := flags.Arg(0), flags.Arg(0)
+	if flags.NArg() == 2 {
+		dst = flags.Arg(1)
+	}
+
+	stats, err := recorder.Recover(src, dst, recorder.RecoverOptions{
+		Level:            *level,
+		KeyframeInterval: *keyframes,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Recovered %d packets to %s: %d -> %d bytes\n", stats.Packets, dst, stats.InputSize, stats.OutputSize)
+	if stats.Damaged > 0 {
+		fmt.Printf("  Skipped %d damaged regions (%d bytes)\n", stats.Damaged, stats.Skipped)
+	}
+	if stats.Dropped > 0 {
+		fmt.Printf("  Dropped %d delta packets whose keyframe was damaged\n", stats.Dropped)
+	}
+	return nil
+}
+
+// setups lists the player car setups of a recording
+func setups(args []string) error {
+	flags := flag.NewFlagSet("setups", flag.ExitOnError)
+	lap := flags.Int("lap", 0, "only show the setup active on this lap")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s setups [flags] <recording.f1tr>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 || *lap < 0 || *lap > 255 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	history, err := recorder.ReadSetups(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *lap > 0 {
+		setup, ok := history.SetupForLap(uint8(*lap))
+		if !ok {
+			return fmt.Errorf("no setup recorded by lap %d", *lap)
+		}
+		fmt.Printf("🔧 Setup on lap %d: %+v\n", *lap, setup)
+		return nil
+	}
+
+	snapshots := history.Snapshots()
+	if len(snapshots) == 0 {
+		fmt.Println("No setups recorded")
+	}
+	for _, snap := range snapshots {
+		fmt.Printf("🔧 Setup from lap %d: wings %d/%d, brake bias %d%%, fuel %.1f kg\n",
+			snap.Lap, snap.Setup.FrontWing, snap.Setup.RearWing, snap.Setup.BrakeBias, snap.Setup.FuelLoad)
+	}
+	return nil
+}
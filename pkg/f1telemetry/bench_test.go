@@ -0,0 +1,112 @@
+package f1telemetry
+
+import (
+	"encoding"
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+// newBenchPacket returns a zeroed F1 25 packet of the given type
+func newBenchPacket(b *testing.B, id PacketType) []byte {
+	size, err := PacketSize(PacketFormat2025, id)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := make([]byte, size)
+	binary.LittleEndian.PutUint16(data, PacketFormat2025)
+	data[6] = uint8(id)
+	return data
+}
+
+// benchZeroAlloc fails if f allocates, then benchmarks it
+func benchZeroAlloc(b *testing.B, f func()) {
+	if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+		b.Fatalf("%v allocations per packet, want 0", allocs)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f()
+	}
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	packets := []struct {
+		id  PacketType
+		pkt encoding.BinaryUnmarshaler
+	}{
+		{PacketMotion, &PacketMotionData{}},
+		{PacketSession, &PacketSessionData{}},
+		{PacketLapData, &LapDataPacket{}},
+		{PacketParticipants, &PacketParticipantsData{}},
+		{PacketCarSetups, &PacketCarSetupData{}},
+		{PacketCarTelemetry, &PacketCarTelemetryData{}},
+		{PacketCarStatus, &PacketCarStatusData{}},
+		{PacketFinalClassification, &PacketFinalClassificationData{}},
+		{PacketLobbyInfo, &PacketLobbyInfoData{}},
+		{PacketCarDamage, &PacketCarDamageData{}},
+		{PacketSessionHistory, &PacketSessionHistoryData{}},
+		{PacketTyreSets, &PacketTyreSetsData{}},
+		{PacketMotionEx, &PacketMotionExData{}},
+		{PacketTimeTrial, &PacketTimeTrialData{}},
+		{PacketLapPositions, &PacketLapPositionsData{}},
+	}
+
+	for _, p := range packets {
+		p := p
+		b.Run(GetPacketTypeName(uint8(p.id)), func(b *testing.B) {
+			data := newBenchPacket(b, p.id)
+			b.SetBytes(int64(len(data)))
+			benchZeroAlloc(b, func() {
+				if err := p.pkt.UnmarshalBinary(data); err != nil {
+					b.Fatal(err)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkUpdateTelemetryData(b *testing.B) {
+	data := newBenchPacket(b, PacketCarTelemetry)
+	var pkt PacketCarTelemetryData
+	var t TelemetryData
+	benchZeroAlloc(b, func() {
+		if err := pkt.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+		pkt.UpdateTelemetryData(0, &t)
+	})
+}
+
+func BenchmarkGetPacketTypeName(b *testing.B) {
+	var id uint8
+	benchZeroAlloc(b, func() {
+		GetPacketTypeName(id % 16)
+		id++
+	})
+}
+
+func BenchmarkReceiver(b *testing.B) {
+	recv := NewReceiver(ReceiverConfig{Address: "127.0.0.1", BufferSize: 2048})
+	if err := recv.Start(); err != nil {
+		b.Skip(err)
+	}
+	defer recv.Stop()
+
+	conn, err := net.DialUDP("udp", nil, recv.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+
+	data := newBenchPacket(b, PacketCarTelemetry)
+	b.SetBytes(int64(len(data)))
+	benchZeroAlloc(b, func() {
+		if _, err := conn.Write(data); err != nil {
+			b.Fatal(err)
+		}
+		packet := <-recv.Packets()
+		packet.Release()
+	})
+}
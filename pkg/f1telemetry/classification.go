@@ -0,0 +1,50 @@
+package f1telemetry
+
+import "time"
+
+// FinalClassificationPacketSize is the size of a Final Classification packet (ID 8)
+const FinalClassificationPacketSize = 1042
+
+// MaxTyreStints is the number of tyre stint slots per car
+const MaxTyreStints = 8
+
+// FinalClassificationData holds the end-of-race result for a single car
+type FinalClassificationData struct {
+	Position          uint8
+	NumLaps           uint8
+	GridPosition      uint8
+	Points            uint8
+	NumPitStops       uint8
+	ResultStatus      uint8   // 0 = invalid, 1 = inactive, 2 = active, 3 = finished ...
+	ResultReason      uint8   `f1:"since=2025"` // 0 = invalid, 1 = retired, 2 = finished ...
+	BestLapTimeInMS   uint32  `unit:"ms"`
+	TotalRaceTime     float64 `unit:"s"` // Without penalties
+	PenaltiesTime     uint8   `unit:"s"`
+	NumPenalties      uint8
+	NumTyreStints     uint8
+	TyreStintsActual  [MaxTyreStints]uint8
+	TyreStintsVisual  [MaxTyreStints]uint8
+	TyreStintsEndLaps [MaxTyreStints]uint8
+}
+
+// PacketFinalClassificationData is the decoded Final Classification packet (ID 8)
+type PacketFinalClassificationData struct {
+	Header             PacketHeader
+	NumCars            uint8
+	ClassificationData [MaxCars]FinalClassificationData
+}
+
+// BestLapTime returns the best lap time as a duration
+func (f *FinalClassificationData) BestLapTime() time.Duration {
+	return time.Duration(f.BestLapTimeInMS) * time.Millisecond
+}
+
+// ParseFinalClassificationPacket decodes the results for all cars from packet ID 8
+func ParseFinalClassificationPacket(data []byte) (*PacketFinalClassificationData, error) {
+	return parsePacket[PacketFinalClassificationData](data, PacketFinalClassification)
+}
+
+// UnmarshalBinary decodes a Final Classification packet (ID 8) into p, reusing its storage
+func (p *PacketFinalClassificationData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketFinalClassification, p)
+}
@@ -0,0 +1,47 @@
+package f1telemetry
+
+// CarDamagePacketSize is the size of a Car Damage packet (ID 10)
+const CarDamagePacketSize = 1041
+
+// CarDamageData holds damage and wear for a single car. Wheel arrays are
+// ordered RL, RR, FL, FR and all values are percentages unless noted.
+type CarDamageData struct {
+	TyresWear            [4]float32 `unit:"%"`
+	TyresDamage          [4]uint8   `unit:"%"`
+	BrakesDamage         [4]uint8   `unit:"%"`
+	TyreBlisters         [4]uint8   `f1:"since=2025" unit:"%"`
+	FrontLeftWingDamage  uint8
+	FrontRightWingDamage uint8
+	RearWingDamage       uint8
+	FloorDamage          uint8
+	DiffuserDamage       uint8
+	SidepodDamage        uint8
+	DRSFault             uint8 // 0 = OK, 1 = fault
+	ERSFault             uint8 // 0 = OK, 1 = fault
+	GearBoxDamage        uint8
+	EngineDamage         uint8
+	EngineMGUHWear       uint8
+	EngineESWear         uint8
+	EngineCEWear         uint8
+	EngineICEWear        uint8
+	EngineMGUKWear       uint8
+	EngineTCWear         uint8
+	EngineBlown          uint8 // 0 = OK, 1 = fault
+	EngineSeized         uint8 // 0 = OK, 1 = fault
+}
+
+// PacketCarDamageData is the decoded Car Damage packet (ID 10)
+type PacketCarDamageData struct {
+	Header        PacketHeader
+	CarDamageData [MaxCars]CarDamageData
+}
+
+// ParseCarDamagePacket decodes damage for all cars from packet ID 10
+func ParseCarDamagePacket(data []byte) (*PacketCarDamageData, error) {
+	return parsePacket[PacketCarDamageData](data, PacketCarDamage)
+}
+
+// UnmarshalBinary decodes a Car Damage packet (ID 10) into p, reusing its storage
+func (p *PacketCarDamageData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketCarDamage, p)
+}
@@ -0,0 +1,122 @@
+package f1telemetry
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// packetTypes maps each packet ID to the struct that declares its layout
+var packetTypes = [numPacketTypes]reflect.Type{
+	PacketMotion:              reflect.TypeOf(PacketMotionData{}),
+	PacketSession:             reflect.TypeOf(PacketSessionData{}),
+	PacketLapData:             reflect.TypeOf(LapDataPacket{}),
+	PacketEvent:               reflect.TypeOf(eventPacket{}),
+	PacketParticipants:        reflect.TypeOf(PacketParticipantsData{}),
+	PacketCarSetups:           reflect.TypeOf(PacketCarSetupData{}),
+	PacketCarTelemetry:        reflect.TypeOf(PacketCarTelemetryData{}),
+	PacketCarStatus:           reflect.TypeOf(PacketCarStatusData{}),
+	PacketFinalClassification: reflect.TypeOf(PacketFinalClassificationData{}),
+	PacketLobbyInfo:           reflect.TypeOf(PacketLobbyInfoData{}),
+	PacketCarDamage:           reflect.TypeOf(PacketCarDamageData{}),
+	PacketSessionHistory:      reflect.TypeOf(PacketSessionHistoryData{}),
+	PacketTyreSets:            reflect.TypeOf(PacketTyreSetsData{}),
+	PacketMotionEx:            reflect.TypeOf(PacketMotionExData{}),
+	PacketTimeTrial:           reflect.TypeOf(PacketTimeTrialData{}),
+	PacketLapPositions:        reflect.TypeOf(PacketLapPositionsData{}),
+}
+
+// packetDecoders maps each packet ID to its decoder
+var packetDecoders = [numPacketTypes]func([]byte) (any, error){
+	PacketMotion:              decoder(ParseMotionPacket),
+	PacketSession:             decoder(ParseSessionPacket),
+	PacketLapData:             decoder(ParseLapDataPacket),
+	PacketEvent:               decoder(ParseEventPacket),
+	PacketParticipants:        decoder(ParseParticipantsPacket),
+	PacketCarSetups:           decoder(ParseCarSetupsPacket),
+	PacketCarTelemetry:        decoder(ParseCarTelemetryPacket),
+	PacketCarStatus:           decoder(ParseCarStatusPacket),
+	PacketFinalClassification: decoder(ParseFinalClassificationPacket),
+	PacketLobbyInfo:           decoder(ParseLobbyInfoPacket),
+	PacketCarDamage:           decoder(ParseCarDamagePacket),
+	PacketSessionHistory:      decoder(ParseSessionHistoryPacket),
+	PacketTyreSets:            decoder(ParseTyreSetsPacket),
+	PacketMotionEx:            decoder(ParseMotionExPacket),
+	PacketTimeTrial:           decoder(ParseTimeTrialPacket),
+	PacketLapPositions:        decoder(ParseLapPositionsPacket),
+}
+
+// DecodePacket decodes any supported packet using the layout of the packet
+// format in its header. The result is a pointer to the packet struct for
+// the packet ID, e.g. *PacketMotionData for Motion packets.
+func DecodePacket(data []byte) (any, error) {
+	header, err := ParseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if int(header.PacketID) >= numPacketTypes {
+		return nil, fmt.Errorf("%w: unknown packet ID %d", ErrUnexpectedPacketType, header.PacketID)
+	}
+	return packetDecoders[header.PacketID](data)
+}
+
+// decoder adapts a typed packet parser to the packetDecoders signature
+func decoder[T any](parse func([]byte) (*T, error)) func([]byte) (any, error) {
+	return func(data []byte) (any, error) {
+		pkt, err := parse(data)
+		if err != nil {
+			return nil, err
+		}
+		return pkt, nil
+	}
+}
+
+// parsePacket decodes a packet into a new T, see unmarshalPacket
+func parsePacket[T any](data []byte, id PacketType) (*T, error) {
+	pkt := new(T)
+	if err := unmarshalPacket(data, id, pkt); err != nil {
+		return nil, err
+	}
+	return pkt, nil
+}
+
+// unmarshalPacket checks a packet and decodes it into dst using the schema
+// of its format. T must be the struct registered in packetTypes. Fields
+// not sent in the format are zeroed, and nothing is allocated.
+func unmarshalPacket[T any](data []byte, id PacketType, dst *T) error {
+	layout, err := checkPacket(data, id)
+	if err != nil {
+		return err
+	}
+
+	schema := layout.schemas[id]
+	if reflect.TypeOf(dst).Elem() != schema.typ {
+		panic(fmt.Sprintf("f1telemetry: %s packets decode into %s, not %T", GetPacketTypeName(uint8(id)), schema.typ, *dst))
+	}
+	var zero T
+	*dst = zero
+	schema.decode(data, unsafe.Pointer(dst))
+	return nil
+}
+
+// checkPacket validates the header, format and size of a packet before
+// decoding and returns the layout of its format
+func checkPacket(data []byte, id PacketType) (*formatLayout, error) {
+	var header PacketHeader
+	if err := header.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	if header.PacketID != uint8(id) {
+		return nil, fmt.Errorf("%w: got %s, want %s", ErrUnexpectedPacketType,
+			GetPacketTypeName(header.PacketID), GetPacketTypeName(uint8(id)))
+	}
+	size, err := PacketSize(header.PacketFormat, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("%w: %s packet is %d bytes, want %d", ErrInvalidPacket,
+			GetPacketTypeName(header.PacketID), len(data), size)
+	}
+	return formatLayouts[header.PacketFormat], nil
+}
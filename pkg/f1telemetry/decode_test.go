@@ -0,0 +1,888 @@
+package f1telemetry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wireValue is a value written little-endian at an offset of a packet
+type wireValue struct {
+	off int
+	v   any
+}
+
+// testHeader is the header of every packet built by buildPacket, apart
+// from the format and packet ID
+var testHeader = PacketHeader{
+	GameMajorVersion:        1,
+	GameMinorVersion:        12,
+	PacketVersion:           1,
+	SessionUID:              0x0123456789ABCDEF,
+	SessionTime:             512.5,
+	FrameIdentifier:         9000,
+	OverallFrameIdentifier:  9100,
+	PlayerCarIndex:          21,
+	SecondaryPlayerCarIndex: 255,
+}
+
+// buildPacket returns a zeroed packet of the wire size of the type in the
+// format, with testHeader and the given values. The offsets are worked
+// out from the spec of each year, not from the schemas under test.
+func buildPacket(t *testing.T, format uint16, id PacketType, values []wireValue) []byte {
+	t.Helper()
+	size, err := PacketSize(format, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := testHeader
+	h.PacketFormat = format
+	h.GameYear = uint8(format % 100)
+	h.PacketID = uint8(id)
+
+	data := make([]byte, size)
+	values = append([]wireValue{{0, h}}, values...)
+	for _, v := range values {
+		var buf bytes.Buffer
+		if err := binary.Write(&buf, binary.LittleEndian, v.v); err != nil {
+			t.Fatal(err)
+		}
+		if v.off+buf.Len() > size {
+			t.Fatalf("%T at %d does not fit a %d byte packet", v.v, v.off, size)
+		}
+		copy(data[v.off:], buf.Bytes())
+	}
+	return data
+}
+
+// expect fails unless a decoded field has the wanted value
+func expect(t *testing.T, field string, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("%s = %v, want %v", field, got, want)
+	}
+}
+
+// testName returns a name of n bytes, to tell the name lengths apart
+func testName(n int) string {
+	return strings.Repeat("Ab", n)[:n]
+}
+
+// nameBytes returns name as a null padded name field
+func nameBytes(name string) [MaxNameSize]byte {
+	var b [MaxNameSize]byte
+	copy(b[:], name)
+	return b
+}
+
+// decodeCase is a packet of one format and the values decoded from it
+type decodeCase struct {
+	name   string
+	format uint16
+	id     PacketType
+	values []wireValue
+	check  func(t *testing.T, pkt any)
+}
+
+// decodeCases returns a case for every packet type in every format that
+// sends it. Each checks fields around the arrays whose length depends on
+// the format and after every field added in a later year, so a field read
+// in the wrong format shifts the values checked after it.
+func decodeCases() []decodeCase {
+	var cases []decodeCase
+	add := func(name string, id PacketType, formats []uint16, values []wireValue, check func(*testing.T, any)) {
+		for _, format := range formats {
+			cases = append(cases, decodeCase{name, format, id, values, check})
+		}
+	}
+	all := []uint16{PacketFormat2023, PacketFormat2024, PacketFormat2025}
+	f23 := []uint16{PacketFormat2023}
+	f24 := []uint16{PacketFormat2024}
+	f25 := []uint16{PacketFormat2025}
+	since24 := []uint16{PacketFormat2024, PacketFormat2025}
+	upTo24 := []uint16{PacketFormat2023, PacketFormat2024}
+
+	// Motion: 60 bytes per car in every format
+	add("motion", PacketMotion, all, []wireValue{
+		{29 + 12, float32(88)},
+		{29 + 21*60, float32(-512.25)},
+		{29 + 21*60 + 24, int16(-32767)},
+		{29 + 21*60 + 34, int16(123)},
+		{29 + 21*60 + 36, float32(1.5)},
+		{29 + 21*60 + 56, float32(-0.25)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketMotionData)
+		c := &p.CarMotionData[21]
+		expect(t, "CarMotionData[0].WorldVelocityX", p.CarMotionData[0].WorldVelocityX, float32(88))
+		expect(t, "WorldPositionX", c.WorldPositionX, float32(-512.25))
+		expect(t, "WorldForwardDirX", c.WorldForwardDirX, int16(-32767))
+		expect(t, "WorldRightDirZ", c.WorldRightDirZ, int16(123))
+		expect(t, "GForceLateral", c.GForceLateral, float32(1.5))
+		expect(t, "Roll", c.Roll, float32(-0.25))
+	})
+
+	// Session: 56 forecast samples in F1 23, 64 and the F1 24 settings
+	// after them since
+	add("session", PacketSession, f23, []wireValue{
+		{29, uint8(3)},
+		{30, int8(-5)},
+		{33, uint16(7004)},
+		{40, uint16(3600)},
+		{47, uint8(21)},
+		{48 + 20*5, float32(0.75)},
+		{48 + 20*5 + 4, int8(3)},
+		{153, uint8(2)},
+		{155, uint8(56)},
+		{156 + 55*8, uint8(13)},
+		{156 + 55*8 + 7, uint8(80)},
+		{604, uint8(1)},
+		{605, uint8(90)},
+		{614, uint32(0xDEADBEEF)},
+		{631, uint8(5)},
+		{632, uint32(840)},
+		{643, uint8(2)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketSessionData)
+		checkSession(t, p, 55)
+		expect(t, "EqualCarPerformance", p.EqualCarPerformance, uint8(0))
+		expect(t, "Sector3LapDistanceStart", p.Sector3LapDistanceStart, float32(0))
+	})
+	add("session", PacketSession, since24, []wireValue{
+		{29, uint8(3)},
+		{30, int8(-5)},
+		{33, uint16(7004)},
+		{40, uint16(3600)},
+		{47, uint8(21)},
+		{48 + 20*5, float32(0.75)},
+		{48 + 20*5 + 4, int8(3)},
+		{153, uint8(2)},
+		{155, uint8(64)},
+		{156 + 63*8, uint8(13)},
+		{156 + 63*8 + 7, uint8(80)},
+		{668, uint8(1)},
+		{669, uint8(90)},
+		{678, uint32(0xDEADBEEF)},
+		{695, uint8(5)},
+		{696, uint32(840)},
+		{707, uint8(2)},
+		{708, uint8(1)},
+		{731, uint8(1)},
+		{732, uint8(4)},
+		{744, uint8(13)},
+		{745, float32(1800.5)},
+		{749, float32(4200.25)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketSessionData)
+		checkSession(t, p, 63)
+		expect(t, "EqualCarPerformance", p.EqualCarPerformance, uint8(1))
+		expect(t, "AffectsLicenceLevelMP", p.AffectsLicenceLevelMP, uint8(1))
+		expect(t, "NumSessionsInWeekend", p.NumSessionsInWeekend, uint8(4))
+		expect(t, "WeekendStructure[11]", p.WeekendStructure[11], uint8(13))
+		expect(t, "Sector2LapDistanceStart", p.Sector2LapDistanceStart, float32(1800.5))
+		expect(t, "Sector3LapDistanceStart", p.Sector3LapDistanceStart, float32(4200.25))
+	})
+
+	// Lap Data: 50 bytes per car in F1 23, 57 with the minutes parts and
+	// the speed trap since F1 24
+	add("lap data", PacketLapData, f23, []wireValue{
+		{1079, uint32(91234)},
+		{1079 + 8, uint16(30123)},
+		{1079 + 10, uint8(1)},
+		{1079 + 14, uint16(1500)},
+		{1079 + 16, uint16(65000)},
+		{1079 + 18, float32(-12.5)},
+		{1079 + 30, uint8(7)},
+		{1079 + 41, uint8(3)},
+		{1079 + 49, uint8(1)},
+		{1129, uint8(21)},
+		{1130, uint8(255)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*LapDataPacket)
+		l := &p.LapData[21]
+		checkLapData(t, p)
+		expect(t, "DeltaToRaceLeaderMSPart", l.DeltaToRaceLeaderMSPart, uint16(65000))
+		expect(t, "DeltaToCarInFrontMinutesPart", l.DeltaToCarInFrontMinutesPart, uint8(0))
+		expect(t, "DeltaToRaceLeaderMinutesPart", l.DeltaToRaceLeaderMinutesPart, uint8(0))
+		expect(t, "SpeedTrapFastestSpeed", l.SpeedTrapFastestSpeed, float32(0))
+	})
+	add("lap data", PacketLapData, since24, []wireValue{
+		{1226, uint32(91234)},
+		{1226 + 8, uint16(30123)},
+		{1226 + 10, uint8(1)},
+		{1226 + 14, uint16(1500)},
+		{1226 + 16, uint8(1)},
+		{1226 + 17, uint16(5000)},
+		{1226 + 19, uint8(2)},
+		{1226 + 20, float32(-12.5)},
+		{1226 + 32, uint8(7)},
+		{1226 + 43, uint8(3)},
+		{1226 + 51, uint8(1)},
+		{1226 + 52, float32(331.5)},
+		{1226 + 56, uint8(12)},
+		{1283, uint8(21)},
+		{1284, uint8(255)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*LapDataPacket)
+		l := &p.LapData[21]
+		checkLapData(t, p)
+		expect(t, "DeltaToCarInFrontMinutesPart", l.DeltaToCarInFrontMinutesPart, uint8(1))
+		expect(t, "DeltaToRaceLeaderMSPart", l.DeltaToRaceLeaderMSPart, uint16(5000))
+		expect(t, "DeltaToRaceLeader", l.DeltaToRaceLeader(), 2*time.Minute+5*time.Second)
+		expect(t, "SpeedTrapFastestSpeed", l.SpeedTrapFastestSpeed, float32(331.5))
+		expect(t, "SpeedTrapFastestLap", l.SpeedTrapFastestLap, uint8(12))
+	})
+
+	// Participants: 48 byte names up to F1 24, 32 byte names and livery
+	// colours in F1 25, tech level since F1 24
+	long := testName(47)
+	add("participants", PacketParticipants, f23, []wireValue{
+		{29, uint8(20)},
+		{1248, uint8(1)},
+		{1248 + 3, uint8(3)},
+		{1248 + 6, uint8(10)},
+		{1248 + 7, nameBytes(long)},
+		{1248 + 55, uint8(1)},
+		{1248 + 57, uint8(3)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketParticipantsData)
+		d := &p.Participants[21]
+		checkParticipant(t, p, long, 3)
+		expect(t, "TechLevel", d.TechLevel, uint16(0))
+	})
+	add("participants", PacketParticipants, f24, []wireValue{
+		{29, uint8(20)},
+		{1290, uint8(1)},
+		{1290 + 3, uint8(3)},
+		{1290 + 6, uint8(10)},
+		{1290 + 7, nameBytes(long)},
+		{1290 + 55, uint8(1)},
+		{1290 + 57, uint16(3500)},
+		{1290 + 59, uint8(3)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketParticipantsData)
+		d := &p.Participants[21]
+		checkParticipant(t, p, long, 3)
+		expect(t, "TechLevel", d.TechLevel, uint16(3500))
+		expect(t, "NumColours", d.NumColours, uint8(0))
+	})
+	short := testName(31)
+	add("participants", PacketParticipants, f25, []wireValue{
+		{29, uint8(20)},
+		{1227, uint8(1)},
+		{1227 + 3, uint8(3)},
+		{1227 + 6, uint8(10)},
+		{1227 + 7, []byte(short)},
+		{1227 + 39, uint8(1)},
+		{1227 + 41, uint16(3500)},
+		{1227 + 43, uint8(4)},
+		{1227 + 44, uint8(2)},
+		{1227 + 45, uint8(255)},
+		{1227 + 56, uint8(7)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketParticipantsData)
+		d := &p.Participants[21]
+		checkParticipant(t, p, short, 4)
+		expect(t, "TechLevel", d.TechLevel, uint16(3500))
+		expect(t, "NumColours", d.NumColours, uint8(2))
+		expect(t, "LiveryColours[0].Red", d.LiveryColours[0].Red, uint8(255))
+		expect(t, "LiveryColours[3].Blue", d.LiveryColours[3].Blue, uint8(7))
+	})
+
+	// Car Setups: 49 bytes per car in F1 23, 50 with engine braking and
+	// the next front wing value since F1 24
+	add("car setups", PacketCarSetups, f23, []wireValue{
+		{1058, uint8(30)},
+		{1058 + 4, float32(-3.5)},
+		{1058 + 27, uint8(56)},
+		{1058 + 28, float32(22.5)},
+		{1058 + 44, uint8(6)},
+		{1058 + 45, float32(12.25)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketCarSetupData)
+		s := &p.CarSetups[21]
+		checkSetup(t, s)
+		expect(t, "EngineBraking", s.EngineBraking, uint8(0))
+		expect(t, "NextFrontWingValue", p.NextFrontWingValue, float32(0))
+	})
+	add("car setups", PacketCarSetups, since24, []wireValue{
+		{1079, uint8(30)},
+		{1079 + 4, float32(-3.5)},
+		{1079 + 27, uint8(56)},
+		{1079 + 28, uint8(80)},
+		{1079 + 29, float32(22.5)},
+		{1079 + 41, float32(23.5)},
+		{1079 + 45, uint8(6)},
+		{1079 + 46, float32(12.25)},
+		{1129, float32(31)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketCarSetupData)
+		s := &p.CarSetups[21]
+		checkSetup(t, s)
+		expect(t, "EngineBraking", s.EngineBraking, uint8(80))
+		expect(t, "FrontRightTyrePressure", s.FrontRightTyrePressure, float32(23.5))
+		expect(t, "NextFrontWingValue", p.NextFrontWingValue, float32(31))
+	})
+
+	// Car Telemetry: 60 bytes per car in every format
+	add("car telemetry", PacketCarTelemetry, all, []wireValue{
+		{1289, uint16(312)},
+		{1289 + 2, float32(1)},
+		{1289 + 10, float32(0.25)},
+		{1289 + 15, int8(-1)},
+		{1289 + 16, uint16(11500)},
+		{1289 + 18, uint8(1)},
+		{1289 + 20, uint16(0x7FFF)},
+		{1289 + 28, uint16(900)},
+		{1289 + 30, [4]uint8{90, 91, 92, 93}},
+		{1289 + 38, uint16(110)},
+		{1289 + 40, float32(23.5)},
+		{1289 + 56, [4]uint8{0, 1, 2, 3}},
+		{1349, uint8(255)},
+		{1350, uint8(1)},
+		{1351, int8(7)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketCarTelemetryData)
+		c := &p.CarTelemetryData[21]
+		expect(t, "Speed", c.Speed, uint16(312))
+		expect(t, "Throttle", c.Throttle, float32(1))
+		expect(t, "Brake", c.Brake, float32(0.25))
+		expect(t, "Gear", c.Gear, int8(-1))
+		expect(t, "EngineRPM", c.EngineRPM, uint16(11500))
+		expect(t, "DRS", c.DRS, uint8(1))
+		expect(t, "RevLightsBitValue", c.RevLightsBitValue, uint16(0x7FFF))
+		expect(t, "BrakesTemperature[3]", c.BrakesTemperature[3], uint16(900))
+		expect(t, "TyresSurfaceTemperature", c.TyresSurfaceTemperature, [4]uint8{90, 91, 92, 93})
+		expect(t, "EngineTemperature", c.EngineTemperature, uint16(110))
+		expect(t, "TyresPressure[0]", c.TyresPressure[0], float32(23.5))
+		expect(t, "SurfaceType", c.SurfaceType, [4]uint8{0, 1, 2, 3})
+		expect(t, "MFDPanelIndex", p.MFDPanelIndex, uint8(255))
+		expect(t, "MFDPanelIndexSecondaryPlayer", p.MFDPanelIndexSecondaryPlayer, uint8(1))
+		expect(t, "SuggestedGear", p.SuggestedGear, int8(7))
+	})
+
+	// Car Status: 55 bytes per car in every format
+	add("car status", PacketCarStatus, all, []wireValue{
+		{1184, uint8(2)},
+		{1184 + 5, float32(50.5)},
+		{1184 + 17, uint16(13000)},
+		{1184 + 22, uint8(1)},
+		{1184 + 23, uint16(120)},
+		{1184 + 28, int8(-1)},
+		{1184 + 29, float32(600000)},
+		{1184 + 37, float32(4e6)},
+		{1184 + 41, uint8(3)},
+		{1184 + 50, float32(1e6)},
+		{1184 + 54, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		c := &pkt.(*PacketCarStatusData).CarStatusData[21]
+		expect(t, "TractionControl", c.TractionControl, uint8(2))
+		expect(t, "FuelInTank", c.FuelInTank, float32(50.5))
+		expect(t, "MaxRPM", c.MaxRPM, uint16(13000))
+		expect(t, "DRSAllowed", c.DRSAllowed, uint8(1))
+		expect(t, "DRSActivationDistance", c.DRSActivationDistance, uint16(120))
+		expect(t, "VehicleFIAFlags", c.VehicleFIAFlags, int8(-1))
+		expect(t, "EnginePowerICE", c.EnginePowerICE, float32(600000))
+		expect(t, "ERSStoreEnergy", c.ERSStoreEnergy, float32(4e6))
+		expect(t, "ERSDeployMode", c.ERSDeployMode, uint8(3))
+		expect(t, "ERSDeployedThisLap", c.ERSDeployedThisLap, float32(1e6))
+		expect(t, "NetworkPaused", c.NetworkPaused, uint8(1))
+	})
+
+	// Final Classification: 45 bytes per car up to F1 24, 46 with the
+	// result reason in F1 25
+	add("final classification", PacketFinalClassification, upTo24, []wireValue{
+		{29, uint8(20)},
+		{975, uint8(20)},
+		{975 + 5, uint8(3)},
+		{975 + 6, uint32(81234)},
+		{975 + 10, float64(5400.125)},
+		{975 + 20, uint8(2)},
+		{975 + 21, [8]uint8{16, 17}},
+		{975 + 37, [8]uint8{20, 57}},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketFinalClassificationData)
+		checkClassification(t, p)
+		expect(t, "ResultReason", p.ClassificationData[21].ResultReason, uint8(0))
+	})
+	add("final classification", PacketFinalClassification, f25, []wireValue{
+		{29, uint8(20)},
+		{996, uint8(20)},
+		{996 + 5, uint8(3)},
+		{996 + 6, uint8(2)},
+		{996 + 7, uint32(81234)},
+		{996 + 11, float64(5400.125)},
+		{996 + 21, uint8(2)},
+		{996 + 22, [8]uint8{16, 17}},
+		{996 + 38, [8]uint8{20, 57}},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketFinalClassificationData)
+		checkClassification(t, p)
+		expect(t, "ResultReason", p.ClassificationData[21].ResultReason, uint8(2))
+	})
+
+	// Lobby Info: names as in Participants, the telemetry settings and
+	// tech level since F1 24
+	add("lobby info", PacketLobbyInfo, f23, []wireValue{
+		{29, uint8(22)},
+		{1164 + 1, uint8(255)},
+		{1164 + 3, uint8(6)},
+		{1164 + 4, nameBytes(long)},
+		{1164 + 52, uint8(44)},
+		{1164 + 53, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketLobbyInfoData)
+		checkLobby(t, p, long)
+		expect(t, "TechLevel", p.LobbyPlayers[21].TechLevel, uint16(0))
+	})
+	add("lobby info", PacketLobbyInfo, f24, []wireValue{
+		{29, uint8(22)},
+		{1248 + 1, uint8(255)},
+		{1248 + 3, uint8(6)},
+		{1248 + 4, nameBytes(long)},
+		{1248 + 52, uint8(44)},
+		{1248 + 53, uint8(1)},
+		{1248 + 55, uint16(2000)},
+		{1248 + 57, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketLobbyInfoData)
+		checkLobby(t, p, long)
+		expect(t, "YourTelemetry", p.LobbyPlayers[21].YourTelemetry, uint8(1))
+		expect(t, "TechLevel", p.LobbyPlayers[21].TechLevel, uint16(2000))
+	})
+	add("lobby info", PacketLobbyInfo, f25, []wireValue{
+		{29, uint8(22)},
+		{912 + 1, uint8(255)},
+		{912 + 3, uint8(6)},
+		{912 + 4, []byte(short)},
+		{912 + 36, uint8(44)},
+		{912 + 37, uint8(1)},
+		{912 + 39, uint16(2000)},
+		{912 + 41, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketLobbyInfoData)
+		checkLobby(t, p, short)
+		expect(t, "YourTelemetry", p.LobbyPlayers[21].YourTelemetry, uint8(1))
+		expect(t, "TechLevel", p.LobbyPlayers[21].TechLevel, uint16(2000))
+	})
+
+	// Car Damage: 42 bytes per car up to F1 24, 46 with tyre blisters in
+	// F1 25
+	add("car damage", PacketCarDamage, upTo24, []wireValue{
+		{911 + 12, float32(45.5)},
+		{911 + 16, [4]uint8{1, 2, 3, 4}},
+		{911 + 20, [4]uint8{5, 6, 7, 8}},
+		{911 + 24, uint8(30)},
+		{911 + 30, uint8(1)},
+		{911 + 41, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		d := &pkt.(*PacketCarDamageData).CarDamageData[21]
+		checkDamage(t, d)
+		expect(t, "TyreBlisters", d.TyreBlisters, [4]uint8{})
+	})
+	add("car damage", PacketCarDamage, f25, []wireValue{
+		{995 + 12, float32(45.5)},
+		{995 + 16, [4]uint8{1, 2, 3, 4}},
+		{995 + 20, [4]uint8{5, 6, 7, 8}},
+		{995 + 24, [4]uint8{9, 10, 11, 12}},
+		{995 + 28, uint8(30)},
+		{995 + 34, uint8(1)},
+		{995 + 45, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		d := &pkt.(*PacketCarDamageData).CarDamageData[21]
+		checkDamage(t, d)
+		expect(t, "TyreBlisters", d.TyreBlisters, [4]uint8{9, 10, 11, 12})
+	})
+
+	// Session History: the same in every format
+	add("session history", PacketSessionHistory, all, []wireValue{
+		{29, uint8(21)},
+		{30, uint8(100)},
+		{31, uint8(8)},
+		{35, uint8(42)},
+		{36, uint32(95500)},
+		{36 + 99*14, uint32(95123)},
+		{36 + 99*14 + 10, uint16(31000)},
+		{36 + 99*14 + 12, uint8(1)},
+		{36 + 99*14 + 13, uint8(0x0F)},
+		{1436 + 7*3, uint8(255)},
+		{1436 + 7*3 + 2, uint8(16)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketSessionHistoryData)
+		lap := &p.LapHistoryData[99]
+		expect(t, "CarIdx", p.CarIdx, uint8(21))
+		expect(t, "len(Laps)", len(p.Laps()), 100)
+		expect(t, "len(Stints)", len(p.Stints()), 8)
+		expect(t, "BestSector3LapNum", p.BestSector3LapNum, uint8(42))
+		expect(t, "LapHistoryData[0].LapTimeInMS", p.LapHistoryData[0].LapTimeInMS, uint32(95500))
+		expect(t, "LapTimeInMS", lap.LapTimeInMS, uint32(95123))
+		expect(t, "Sector3Time", lap.Sector3Time(), 91*time.Second)
+		expect(t, "IsValid", lap.IsValid(), true)
+		expect(t, "TyreStintsHistory[7].EndLap", p.TyreStintsHistory[7].EndLap, uint8(255))
+		expect(t, "TyreStintsHistory[7].TyreVisualCompound", p.TyreStintsHistory[7].TyreVisualCompound, uint8(16))
+	})
+
+	// Tyre Sets: the same in every format
+	add("tyre sets", PacketTyreSets, all, []wireValue{
+		{29, uint8(3)},
+		{30 + 19*10, uint8(16)},
+		{30 + 19*10 + 2, uint8(35)},
+		{30 + 19*10 + 7, int16(-1200)},
+		{30 + 19*10 + 9, uint8(1)},
+		{230, uint8(19)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketTyreSetsData)
+		s := &p.TyreSetData[19]
+		expect(t, "CarIdx", p.CarIdx, uint8(3))
+		expect(t, "ActualTyreCompound", s.ActualTyreCompound, uint8(16))
+		expect(t, "Wear", s.Wear, uint8(35))
+		expect(t, "LapDelta", s.LapDelta(), -1200*time.Millisecond)
+		expect(t, "Fitted", s.Fitted, uint8(1))
+		expect(t, "FittedIdx", p.FittedIdx, uint8(19))
+	})
+
+	// Motion Ex: the aero and roll values since F1 24, pitch and camber
+	// since F1 25
+	motionEx := []wireValue{
+		{29, float32(1.5)},
+		{29 + 7*16 + 12, float32(-2500)},
+		{157, float32(0.25)},
+		{181, float32(0.75)},
+		{197, float32(0.125)},
+		{213, float32(4000)},
+	}
+	add("motion ex", PacketMotionEx, f23, motionEx, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketMotionExData)
+		checkMotionEx(t, p)
+		expect(t, "FrontAeroHeight", p.FrontAeroHeight, float32(0))
+	})
+	add("motion ex", PacketMotionEx, f24, append(motionEx,
+		wireValue{217, float32(0.0625)},
+		wireValue{233, float32(-0.5)},
+	), func(t *testing.T, pkt any) {
+		p := pkt.(*PacketMotionExData)
+		checkMotionEx(t, p)
+		expect(t, "FrontAeroHeight", p.FrontAeroHeight, float32(0.0625))
+		expect(t, "ChassisYaw", p.ChassisYaw, float32(-0.5))
+		expect(t, "ChassisPitch", p.ChassisPitch, float32(0))
+	})
+	add("motion ex", PacketMotionEx, f25, append(motionEx,
+		wireValue{217, float32(0.0625)},
+		wireValue{233, float32(-0.5)},
+		wireValue{237, float32(0.03125)},
+		wireValue{241, float32(-0.0625)},
+		wireValue{269, float32(0.5)},
+	), func(t *testing.T, pkt any) {
+		p := pkt.(*PacketMotionExData)
+		checkMotionEx(t, p)
+		expect(t, "FrontAeroHeight", p.FrontAeroHeight, float32(0.0625))
+		expect(t, "ChassisYaw", p.ChassisYaw, float32(-0.5))
+		expect(t, "ChassisPitch", p.ChassisPitch, float32(0.03125))
+		expect(t, "WheelCamber[0]", p.WheelCamber[0], float32(-0.0625))
+		expect(t, "WheelCamberGain[3]", p.WheelCamberGain[3], float32(0.5))
+	})
+
+	// Time Trial since F1 24 and Lap Positions in F1 25
+	add("time trial", PacketTimeTrial, since24, []wireValue{
+		{29 + 2, uint32(80123)},
+		{53 + 6, uint32(25000)},
+		{77 + 1, uint8(3)},
+		{77 + 23, uint8(1)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketTimeTrialData)
+		expect(t, "PlayerSessionBestDataSet.LapTime", p.PlayerSessionBestDataSet.LapTime(), 80123*time.Millisecond)
+		expect(t, "PersonalBestDataSet.Sector1TimeInMS", p.PersonalBestDataSet.Sector1TimeInMS, uint32(25000))
+		expect(t, "RivalDataSet.TeamID", p.RivalDataSet.TeamID, uint8(3))
+		expect(t, "RivalDataSet.Valid", p.RivalDataSet.Valid, uint8(1))
+	})
+	add("lap positions", PacketLapPositions, f25, []wireValue{
+		{29, uint8(50)},
+		{30, uint8(50)},
+		{31, uint8(1)},
+		{31 + 49*22 + 21, uint8(22)},
+	}, func(t *testing.T, pkt any) {
+		p := pkt.(*PacketLapPositionsData)
+		expect(t, "NumLaps", p.NumLaps, uint8(50))
+		expect(t, "LapStart", p.LapStart, uint8(50))
+		expect(t, "PositionForVehicleIdx[0][0]", p.PositionForVehicleIdx[0][0], uint8(1))
+		expect(t, "PositionForVehicleIdx[49][21]", p.PositionForVehicleIdx[49][21], uint8(22))
+	})
+
+	// Events: the payload follows the 4 byte code, with the fields added
+	// in F1 25 left out before
+	event := func(code string, values ...wireValue) []wireValue {
+		return append([]wireValue{{29, [4]byte([]byte(code))}}, values...)
+	}
+	add("fastest lap event", PacketEvent, all, event(EventFastestLap,
+		wireValue{33, uint8(5)},
+		wireValue{34, float32(78.5)},
+	), func(t *testing.T, pkt any) {
+		d := eventDetails[*FastestLapEvent](t, pkt, EventFastestLap)
+		expect(t, "VehicleIdx", d.VehicleIdx, uint8(5))
+		expect(t, "LapTime", d.LapTime, float32(78.5))
+	})
+	add("speed trap event", PacketEvent, all, event(EventSpeedTrap,
+		wireValue{33, uint8(5)},
+		wireValue{34, float32(340.5)},
+		wireValue{38, uint8(1)},
+		wireValue{40, uint8(7)},
+		wireValue{41, float32(342.25)},
+	), func(t *testing.T, pkt any) {
+		d := eventDetails[*SpeedTrapEvent](t, pkt, EventSpeedTrap)
+		expect(t, "Speed", d.Speed, float32(340.5))
+		expect(t, "IsOverallFastestInSession", d.IsOverallFastestInSession, uint8(1))
+		expect(t, "FastestVehicleIdxInSession", d.FastestVehicleIdxInSession, uint8(7))
+		expect(t, "FastestSpeedInSession", d.FastestSpeedInSession, float32(342.25))
+	})
+	retirement := event(EventRetirement, wireValue{33, uint8(9)}, wireValue{34, uint8(3)})
+	add("retirement event", PacketEvent, upTo24, retirement, func(t *testing.T, pkt any) {
+		d := eventDetails[*RetirementEvent](t, pkt, EventRetirement)
+		expect(t, "VehicleIdx", d.VehicleIdx, uint8(9))
+		expect(t, "Reason", d.Reason, uint8(0))
+	})
+	add("retirement event", PacketEvent, f25, retirement, func(t *testing.T, pkt any) {
+		d := eventDetails[*RetirementEvent](t, pkt, EventRetirement)
+		expect(t, "VehicleIdx", d.VehicleIdx, uint8(9))
+		expect(t, "Reason", d.Reason, uint8(3))
+	})
+	stopGo := event(EventStopGoServed, wireValue{33, uint8(4)}, wireValue{34, float32(10.5)})
+	add("stop go event", PacketEvent, upTo24, stopGo, func(t *testing.T, pkt any) {
+		d := eventDetails[*StopGoPenaltyServedEvent](t, pkt, EventStopGoServed)
+		expect(t, "VehicleIdx", d.VehicleIdx, uint8(4))
+		expect(t, "StopTime", d.StopTime, float32(0))
+	})
+	add("stop go event", PacketEvent, f25, stopGo, func(t *testing.T, pkt any) {
+		d := eventDetails[*StopGoPenaltyServedEvent](t, pkt, EventStopGoServed)
+		expect(t, "VehicleIdx", d.VehicleIdx, uint8(4))
+		expect(t, "StopTime", d.StopTime, float32(10.5))
+	})
+	drsDisabled := event(EventDRSDisabled, wireValue{33, uint8(2)})
+	add("DRS disabled event", PacketEvent, upTo24, drsDisabled, func(t *testing.T, pkt any) {
+		if ev := pkt.(*Event); ev.Code != EventDRSDisabled || ev.Details != nil {
+			t.Errorf("event %s with details %+v, want %s without", ev.Code, ev.Details, EventDRSDisabled)
+		}
+	})
+	add("DRS disabled event", PacketEvent, f25, drsDisabled, func(t *testing.T, pkt any) {
+		d := eventDetails[*DRSDisabledEvent](t, pkt, EventDRSDisabled)
+		expect(t, "Reason", d.Reason, uint8(2))
+	})
+	add("unknown event", PacketEvent, all, event("ABCD", wireValue{33, [4]byte{1, 2, 3, 4}}), func(t *testing.T, pkt any) {
+		d := eventDetails[*UnknownEventDetails](t, pkt, "ABCD")
+		expect(t, "Data", d.Data, [eventDetailsSize]byte{1, 2, 3, 4})
+	})
+
+	return cases
+}
+
+func checkSession(t *testing.T, p *PacketSessionData, lastSample int) {
+	t.Helper()
+	expect(t, "Weather", p.Weather, uint8(3))
+	expect(t, "TrackTemperature", p.TrackTemperature, int8(-5))
+	expect(t, "TrackLength", p.TrackLength, uint16(7004))
+	expect(t, "SessionDuration", p.SessionDuration, uint16(3600))
+	expect(t, "len(ActiveMarshalZones)", len(p.ActiveMarshalZones()), 21)
+	expect(t, "MarshalZones[20]", p.MarshalZones[20], MarshalZone{ZoneStart: 0.75, ZoneFlag: 3})
+	expect(t, "SafetyCarStatus", p.SafetyCarStatus, uint8(2))
+	expect(t, "len(Forecast)", len(p.Forecast()), lastSample+1)
+	expect(t, "last forecast SessionType", p.WeatherForecastSamples[lastSample].SessionType, uint8(13))
+	expect(t, "last forecast RainPercentage", p.WeatherForecastSamples[lastSample].RainPercentage, uint8(80))
+	expect(t, "ForecastAccuracy", p.ForecastAccuracy, uint8(1))
+	expect(t, "AIDifficulty", p.AIDifficulty, uint8(90))
+	expect(t, "SessionLinkIdentifier", p.SessionLinkIdentifier, uint32(0xDEADBEEF))
+	expect(t, "RuleSet", p.RuleSet, uint8(5))
+	expect(t, "TimeOfDay", p.TimeOfDay, uint32(840))
+	expect(t, "NumRedFlagPeriods", p.NumRedFlagPeriods, uint8(2))
+}
+
+func checkLapData(t *testing.T, p *LapDataPacket) {
+	t.Helper()
+	l := &p.LapData[21]
+	expect(t, "LastLapTime", l.LastLapTime(), 91234*time.Millisecond)
+	expect(t, "Sector1Time", l.Sector1Time(), 90123*time.Millisecond)
+	expect(t, "DeltaToCarInFrontMSPart", l.DeltaToCarInFrontMSPart, uint16(1500))
+	expect(t, "LapDistance", l.LapDistance, float32(-12.5))
+	expect(t, "CarPosition", l.CarPosition, uint8(7))
+	expect(t, "GridPosition", l.GridPosition, uint8(3))
+	expect(t, "PitStopShouldServePen", l.PitStopShouldServePen, uint8(1))
+	expect(t, "TimeTrialPBCarIdx", p.TimeTrialPBCarIdx, uint8(21))
+	expect(t, "TimeTrialRivalCarIdx", p.TimeTrialRivalCarIdx, uint8(255))
+}
+
+func checkParticipant(t *testing.T, p *PacketParticipantsData, name string, platform uint8) {
+	t.Helper()
+	d := &p.Participants[21]
+	expect(t, "NumActiveCars", p.NumActiveCars, uint8(20))
+	expect(t, "AIControlled", d.AIControlled, uint8(1))
+	expect(t, "TeamID", d.TeamID, uint8(3))
+	expect(t, "Nationality", d.Nationality, uint8(10))
+	expect(t, "Name", d.NameString(), name)
+	expect(t, "YourTelemetry", d.YourTelemetry, uint8(1))
+	expect(t, "Platform", d.Platform, platform)
+}
+
+func checkSetup(t *testing.T, s *CarSetupData) {
+	t.Helper()
+	expect(t, "FrontWing", s.FrontWing, uint8(30))
+	expect(t, "FrontCamber", s.FrontCamber, float32(-3.5))
+	expect(t, "BrakeBias", s.BrakeBias, uint8(56))
+	expect(t, "RearLeftTyrePressure", s.RearLeftTyrePressure, float32(22.5))
+	expect(t, "Ballast", s.Ballast, uint8(6))
+	expect(t, "FuelLoad", s.FuelLoad, float32(12.25))
+}
+
+func checkClassification(t *testing.T, p *PacketFinalClassificationData) {
+	t.Helper()
+	c := &p.ClassificationData[21]
+	expect(t, "NumCars", p.NumCars, uint8(20))
+	expect(t, "Position", c.Position, uint8(20))
+	expect(t, "ResultStatus", c.ResultStatus, uint8(3))
+	expect(t, "BestLapTime", c.BestLapTime(), 81234*time.Millisecond)
+	expect(t, "TotalRaceTime", c.TotalRaceTime, 5400.125)
+	expect(t, "NumTyreStints", c.NumTyreStints, uint8(2))
+	expect(t, "TyreStintsActual", c.TyreStintsActual, [MaxTyreStints]uint8{16, 17})
+	expect(t, "TyreStintsEndLaps", c.TyreStintsEndLaps, [MaxTyreStints]uint8{20, 57})
+}
+
+func checkLobby(t *testing.T, p *PacketLobbyInfoData, name string) {
+	t.Helper()
+	l := &p.LobbyPlayers[21]
+	expect(t, "len(Players)", len(p.Players()), 22)
+	expect(t, "TeamID", l.TeamID, uint8(255))
+	expect(t, "Platform", l.Platform, uint8(6))
+	expect(t, "Name", l.NameString(), name)
+	expect(t, "CarNumber", l.CarNumber, uint8(44))
+	expect(t, "ReadyStatus", l.ReadyStatus, uint8(1))
+}
+
+func checkDamage(t *testing.T, d *CarDamageData) {
+	t.Helper()
+	expect(t, "TyresWear[3]", d.TyresWear[3], float32(45.5))
+	expect(t, "TyresDamage", d.TyresDamage, [4]uint8{1, 2, 3, 4})
+	expect(t, "BrakesDamage", d.BrakesDamage, [4]uint8{5, 6, 7, 8})
+	expect(t, "FrontLeftWingDamage", d.FrontLeftWingDamage, uint8(30))
+	expect(t, "DRSFault", d.DRSFault, uint8(1))
+	expect(t, "EngineSeized", d.EngineSeized, uint8(1))
+}
+
+func checkMotionEx(t *testing.T, p *PacketMotionExData) {
+	t.Helper()
+	expect(t, "SuspensionPosition[0]", p.SuspensionPosition[0], float32(1.5))
+	expect(t, "WheelLongForce[3]", p.WheelLongForce[3], float32(-2500))
+	expect(t, "HeightOfCOGAboveGround", p.HeightOfCOGAboveGround, float32(0.25))
+	expect(t, "AngularVelocityZ", p.AngularVelocityZ, float32(0.75))
+	expect(t, "FrontWheelsAngle", p.FrontWheelsAngle, float32(0.125))
+	expect(t, "WheelVertForce[3]", p.WheelVertForce[3], float32(4000))
+}
+
+// eventDetails returns the payload of an event, failing unless the event
+// has the code and a payload of type T
+func eventDetails[T EventDetails](t *testing.T, pkt any, code string) T {
+	t.Helper()
+	ev := pkt.(*Event)
+	if ev.Code != code {
+		t.Fatalf("event code %q, want %q", ev.Code, code)
+	}
+	d, ok := ev.Details.(T)
+	if !ok {
+		t.Fatalf("%s event details are %T", code, ev.Details)
+	}
+	return d
+}
+
+func TestDecodePackets(t *testing.T) {
+	for _, c := range decodeCases() {
+		t.Run(fmt.Sprint(c.name, "/", c.format), func(t *testing.T) {
+			data := buildPacket(t, c.format, c.id, c.values)
+			pkt, err := DecodePacket(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := reflect.ValueOf(pkt).Elem().FieldByName("Header").Interface().(PacketHeader)
+			want := testHeader
+			want.PacketFormat = c.format
+			want.GameYear = uint8(c.format % 100)
+			want.PacketID = uint8(c.id)
+			expect(t, "Header", header, want)
+			c.check(t, pkt)
+		})
+	}
+}
+
+func TestDecodeCoversEveryPacket(t *testing.T) {
+	covered := map[uint16]map[PacketType]bool{}
+	for _, c := range decodeCases() {
+		if covered[c.format] == nil {
+			covered[c.format] = map[PacketType]bool{}
+		}
+		covered[c.format][c.id] = true
+	}
+	for _, format := range SupportedFormats() {
+		for id := PacketType(0); int(id) < numPacketTypes; id++ {
+			if _, err := PacketSize(format, id); err == nil && !covered[format][id] {
+				t.Errorf("no test for %s packets in format %d", GetPacketTypeName(uint8(id)), format)
+			}
+		}
+	}
+}
+
+// Decoding into a struct used for a later format clears what the earlier
+// format does not send
+func TestDecodeClearsFieldsNotSent(t *testing.T) {
+	var p PacketParticipantsData
+	for _, format := range []uint16{PacketFormat2024, PacketFormat2025, PacketFormat2023} {
+		data := buildPacket(t, format, PacketParticipants, nil)
+		if err := p.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if p.Participants[0] != (ParticipantData{}) {
+			t.Errorf("format %d left %+v", format, p.Participants[0])
+		}
+
+		// Fill every field, including those the next format does not send
+		for i := range p.Participants {
+			d := &p.Participants[i]
+			d.Name = nameBytes(testName(MaxNameSize))
+			d.TechLevel = 1
+			d.NumColours = 1
+			d.LiveryColours[3].Blue = 1
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	motion := buildPacket(t, PacketFormat2025, PacketMotion, nil)
+	timeTrial := buildPacket(t, PacketFormat2024, PacketTimeTrial, nil)
+	lapPositions := buildPacket(t, PacketFormat2025, PacketLapPositions, nil)
+	withFormat := func(data []byte, format uint16) []byte {
+		data = append([]byte(nil), data...)
+		binary.LittleEndian.PutUint16(data, format)
+		return data
+	}
+	unknownType := append([]byte(nil), motion...)
+	unknownType[6] = 16
+
+	tests := []struct {
+		name  string
+		parse func([]byte) (any, error)
+		data  []byte
+		want  error
+	}{
+		{"short header", DecodePacket, motion[:PacketHeaderSize-1], ErrInvalidPacket},
+		{"short packet", DecodePacket, motion[:len(motion)-1], ErrInvalidPacket},
+		{"long packet", DecodePacket, append(motion, 0), ErrInvalidPacket},
+		{"unknown format", DecodePacket, withFormat(motion, 2022), ErrUnsupportedFormat},
+		{"time trial in F1 23", DecodePacket, withFormat(timeTrial, PacketFormat2023), ErrUnsupportedFormat},
+		{"lap positions in F1 24", DecodePacket, withFormat(lapPositions, PacketFormat2024), ErrUnsupportedFormat},
+		{"wrong type", decoder(ParseSessionPacket), motion, ErrUnexpectedPacketType},
+		{"unknown type", DecodePacket, unknownType, ErrUnexpectedPacketType},
+	}
+	for _, tt := range tests {
+		if _, err := tt.parse(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: returned %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
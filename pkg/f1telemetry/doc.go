@@ -0,0 +1,39 @@
+// Package f1telemetry decodes and receives the UDP telemetry sent by the
+// F1 23, F1 24 and F1 25 games.
+//
+// # Decoding
+//
+// Every packet type has a struct, a ParseX function that returns a new
+// value and an UnmarshalBinary method that decodes into an existing one
+// without allocating. DecodePacket picks the type from the packet header.
+// The packet format (2023, 2024 or 2025) is read from the header too, so
+// the same code decodes all supported games. Fields a format does not
+// send are left zero. Schema describes the wire layout of each packet.
+//
+// # Receiving
+//
+// Receiver listens on one or more UDP endpoints and hands out packets on a
+// channel, with a choice of what to do when the consumer falls behind
+// (see BackpressurePolicy). Receiver can also forward packets to other
+// apps and tracks lost and reordered packets per sender.
+//
+// Pipeline fans packets out from a Source, such as a Receiver or a
+// recording being played back, to stages that each run behind their own
+// buffer. StateTracker is a stage that keeps the state of every car,
+// published a whole frame at a time; OriginTrackers keeps one per sender
+// when several games send to the same receiver.
+//
+// # Compatibility
+//
+// This package follows semantic versioning with the module it belongs to.
+// Within a major version exported identifiers are not removed and their
+// behaviour does not change incompatibly, with these exceptions:
+//
+//   - Packet structs gain fields when a new game format adds data. Use
+//     keyed struct literals and do not depend on their size.
+//   - New packet formats, packet types and event codes are added as the
+//     games define them.
+//   - Counters may be added to the stats structs.
+//
+// Support for a packet format is only dropped in a new major version.
+package f1telemetry
@@ -0,0 +1,83 @@
+package f1telemetry
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a UDP address the receiver listens on. Unicast IPv4 and
+// IPv6 addresses are bound directly; for a multicast group the receiver
+// joins the group on Interface.
+type Endpoint struct {
+	Address   string // IP to bind or multicast group to join, all interfaces if empty
+	Port      int
+	Interface string // Interface for multicast, the system default if empty
+}
+
+// ParseEndpoint parses an endpoint written as host:port, with an optional
+// @interface suffix for multicast groups, e.g. "[ff02::114]:20777@eth0"
+func ParseEndpoint(s string) (Endpoint, error) {
+	addr, iface, _ := strings.Cut(s, "@")
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("invalid endpoint %q: %w", s, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return Endpoint{}, fmt.Errorf("invalid endpoint %q: bad port", s)
+	}
+	if host != "" && net.ParseIP(host) == nil {
+		return Endpoint{}, fmt.Errorf("invalid endpoint %q: bad IP address", s)
+	}
+	return Endpoint{Address: host, Port: port, Interface: iface}, nil
+}
+
+// String formats the endpoint the way ParseEndpoint reads it
+func (e Endpoint) String() string {
+	s := net.JoinHostPort(e.Address, strconv.Itoa(e.Port))
+	if e.Interface != "" {
+		s += "@" + e.Interface
+	}
+	return s
+}
+
+// listen opens a socket for the endpoint
+func (e Endpoint) listen() (*net.UDPConn, error) {
+	addr := &net.UDPAddr{
+		IP:   net.ParseIP(e.Address),
+		Port: e.Port,
+	}
+	if !addr.IP.IsMulticast() {
+		return net.ListenUDP("udp", addr)
+	}
+
+	var iface *net.Interface
+	if e.Interface != "" {
+		var err error
+		if iface, err = net.InterfaceByName(e.Interface); err != nil {
+			return nil, err
+		}
+	}
+	return net.ListenMulticastUDP("udp", iface, addr)
+}
+
+// Origin identifies where a packet was received: the endpoint it arrived
+// on and the address it was sent from
+type Origin struct {
+	Endpoint string // Endpoint.String of the receiving endpoint
+	Source   netip.AddrPort
+}
+
+// String formats the origin as "source via endpoint"
+func (o Origin) String() string {
+	switch {
+	case !o.Source.IsValid():
+		return o.Endpoint
+	case o.Endpoint == "":
+		return o.Source.String()
+	}
+	return o.Source.String() + " via " + o.Endpoint
+}
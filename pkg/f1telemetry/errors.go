@@ -0,0 +1,11 @@
+package f1telemetry
+
+import "errors"
+
+var (
+	ErrInvalidPacket        = errors.New("invalid packet data")
+	ErrUnexpectedPacketType = errors.New("unexpected packet type")
+	ErrUnsupportedFormat    = errors.New("unsupported packet format")
+	ErrTimeout              = errors.New("packet receive timeout")
+	ErrStopped              = errors.New("receiver stopped")
+)
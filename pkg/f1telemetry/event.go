@@ -0,0 +1,248 @@
+package f1telemetry
+
+import "reflect"
+
+// EventPacketSize is the size of an Event packet (ID 3)
+const EventPacketSize = 45
+
+// eventDetailsSize is the size of the EventDataDetails union
+const eventDetailsSize = 12
+
+// Event string codes
+const (
+	EventSessionStarted     = "SSTA"
+	EventSessionEnded       = "SEND"
+	EventFastestLap         = "FTLP"
+	EventRetirement         = "RTMT"
+	EventDRSEnabled         = "DRSE"
+	EventDRSDisabled        = "DRSD"
+	EventTeamMateInPits     = "TMPT"
+	EventChequeredFlag      = "CHQF"
+	EventRaceWinner         = "RCWN"
+	EventPenaltyIssued      = "PENA"
+	EventSpeedTrap          = "SPTP"
+	EventStartLights        = "STLG"
+	EventLightsOut          = "LGOT"
+	EventDriveThroughServed = "DTSV"
+	EventStopGoServed       = "SGSV"
+	EventFlashback          = "FLBK"
+	EventButtonStatus       = "BUTN"
+	EventRedFlag            = "RDFL"
+	EventOvertake           = "OVTK"
+	EventSafetyCar          = "SCAR"
+	EventCollision          = "COLL"
+)
+
+// eventPacket is the wire layout of the Event packet (ID 3). Details is
+// decoded further by code, see eventDetailTypes.
+type eventPacket struct {
+	Header  PacketHeader
+	Code    [4]byte
+	Details [eventDetailsSize]byte
+}
+
+// Event is the decoded Event packet (ID 3)
+type Event struct {
+	Header PacketHeader
+	Code   string
+
+	// Details holds the payload for the event code. It is nil for events
+	// without a payload in the packet format (SSTA, SEND, DRSE, CHQF,
+	// LGOT, RDFL, and DRSD before F1 25) and an *UnknownEventDetails for
+	// codes this package does not know about.
+	Details EventDetails
+}
+
+// EventDetails is implemented by every event payload type
+type EventDetails interface {
+	isEventDetails()
+}
+
+// FastestLapEvent is the payload of FTLP
+type FastestLapEvent struct {
+	VehicleIdx uint8
+	LapTime    float32 `unit:"s"`
+}
+
+// RetirementEvent is the payload of RTMT
+type RetirementEvent struct {
+	VehicleIdx uint8
+	Reason     uint8 `f1:"since=2025"` // 0 = invalid, 1 = retired, 2 = finished, 3 = terminal damage ...
+}
+
+// DRSDisabledEvent is the payload of DRSD
+type DRSDisabledEvent struct {
+	Reason uint8 `f1:"since=2025"` // 0 = wet track, 1 = safety car, 2 = red flag, 3 = min lap not reached
+}
+
+// TeamMateInPitsEvent is the payload of TMPT
+type TeamMateInPitsEvent struct {
+	VehicleIdx uint8
+}
+
+// RaceWinnerEvent is the payload of RCWN
+type RaceWinnerEvent struct {
+	VehicleIdx uint8
+}
+
+// PenaltyEvent is the payload of PENA
+type PenaltyEvent struct {
+	PenaltyType      uint8
+	InfringementType uint8
+	VehicleIdx       uint8
+	OtherVehicleIdx  uint8
+	Time             uint8 `unit:"s"` // Time gained or spent
+	LapNum           uint8
+	PlacesGained     uint8
+}
+
+// SpeedTrapEvent is the payload of SPTP
+type SpeedTrapEvent struct {
+	VehicleIdx                 uint8
+	Speed                      float32 `unit:"km/h"`
+	IsOverallFastestInSession  uint8
+	IsDriverFastestInSession   uint8
+	FastestVehicleIdxInSession uint8
+	FastestSpeedInSession      float32 `unit:"km/h"`
+}
+
+// StartLightsEvent is the payload of STLG
+type StartLightsEvent struct {
+	NumLights uint8
+}
+
+// DriveThroughPenaltyServedEvent is the payload of DTSV
+type DriveThroughPenaltyServedEvent struct {
+	VehicleIdx uint8
+}
+
+// StopGoPenaltyServedEvent is the payload of SGSV
+type StopGoPenaltyServedEvent struct {
+	VehicleIdx uint8
+	StopTime   float32 `f1:"since=2025" unit:"s"`
+}
+
+// FlashbackEvent is the payload of FLBK
+type FlashbackEvent struct {
+	FlashbackFrameIdentifier uint32
+	FlashbackSessionTime     float32 `unit:"s"`
+}
+
+// ButtonsEvent is the payload of BUTN
+type ButtonsEvent struct {
+	ButtonStatus uint32 // Bit flags, see spec appendix
+}
+
+// OvertakeEvent is the payload of OVTK
+type OvertakeEvent struct {
+	OvertakingVehicleIdx     uint8
+	BeingOvertakenVehicleIdx uint8
+}
+
+// SafetyCarEvent is the payload of SCAR
+type SafetyCarEvent struct {
+	SafetyCarType uint8 // 0 = none, 1 = full, 2 = virtual, 3 = formation lap
+	EventType     uint8 // 0 = deployed, 1 = returning, 2 = returned, 3 = resume race
+}
+
+// CollisionEvent is the payload of COLL
+type CollisionEvent struct {
+	Vehicle1Idx uint8
+	Vehicle2Idx uint8
+}
+
+// UnknownEventDetails keeps the raw payload of an unrecognised event code
+type UnknownEventDetails struct {
+	Data [eventDetailsSize]byte
+}
+
+func (*FastestLapEvent) isEventDetails()                {}
+func (*RetirementEvent) isEventDetails()                {}
+func (*DRSDisabledEvent) isEventDetails()               {}
+func (*TeamMateInPitsEvent) isEventDetails()            {}
+func (*RaceWinnerEvent) isEventDetails()                {}
+func (*PenaltyEvent) isEventDetails()                   {}
+func (*SpeedTrapEvent) isEventDetails()                 {}
+func (*StartLightsEvent) isEventDetails()               {}
+func (*DriveThroughPenaltyServedEvent) isEventDetails() {}
+func (*StopGoPenaltyServedEvent) isEventDetails()       {}
+func (*FlashbackEvent) isEventDetails()                 {}
+func (*ButtonsEvent) isEventDetails()                   {}
+func (*OvertakeEvent) isEventDetails()                  {}
+func (*SafetyCarEvent) isEventDetails()                 {}
+func (*CollisionEvent) isEventDetails()                 {}
+func (*UnknownEventDetails) isEventDetails()            {}
+
+// eventDetailTypes maps each known event code to its payload type, nil
+// for events that never carry a payload
+var eventDetailTypes = map[string]reflect.Type{
+	EventSessionStarted:     nil,
+	EventSessionEnded:       nil,
+	EventFastestLap:         reflect.TypeOf(FastestLapEvent{}),
+	EventRetirement:         reflect.TypeOf(RetirementEvent{}),
+	EventDRSEnabled:         nil,
+	EventDRSDisabled:        reflect.TypeOf(DRSDisabledEvent{}),
+	EventTeamMateInPits:     reflect.TypeOf(TeamMateInPitsEvent{}),
+	EventChequeredFlag:      nil,
+	EventRaceWinner:         reflect.TypeOf(RaceWinnerEvent{}),
+	EventPenaltyIssued:      reflect.TypeOf(PenaltyEvent{}),
+	EventSpeedTrap:          reflect.TypeOf(SpeedTrapEvent{}),
+	EventStartLights:        reflect.TypeOf(StartLightsEvent{}),
+	EventLightsOut:          nil,
+	EventDriveThroughServed: reflect.TypeOf(DriveThroughPenaltyServedEvent{}),
+	EventStopGoServed:       reflect.TypeOf(StopGoPenaltyServedEvent{}),
+	EventFlashback:          reflect.TypeOf(FlashbackEvent{}),
+	EventButtonStatus:       reflect.TypeOf(ButtonsEvent{}),
+	EventRedFlag:            nil,
+	EventOvertake:           reflect.TypeOf(OvertakeEvent{}),
+	EventSafetyCar:          reflect.TypeOf(SafetyCarEvent{}),
+	EventCollision:          reflect.TypeOf(CollisionEvent{}),
+}
+
+// eventCodes interns the known event codes so decoding them does not allocate
+var eventCodes = func() map[[4]byte]string {
+	codes := make(map[[4]byte]string, len(eventDetailTypes))
+	for code := range eventDetailTypes {
+		codes[[4]byte([]byte(code))] = code
+	}
+	return codes
+}()
+
+// ParseEventPacket decodes an Event packet (ID 3) and its code-specific details
+func ParseEventPacket(data []byte) (*Event, error) {
+	ev := &Event{}
+	if err := ev.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// UnmarshalBinary decodes an Event packet (ID 3) into e. Unlike the other
+// packet types, the payload in Details is newly allocated.
+func (e *Event) UnmarshalBinary(data []byte) error {
+	var pkt eventPacket
+	if err := unmarshalPacket(data, PacketEvent, &pkt); err != nil {
+		return err
+	}
+
+	e.Header = pkt.Header
+	e.Details = nil
+	code, known := eventCodes[pkt.Code]
+	if !known {
+		e.Code = string(pkt.Code[:])
+		e.Details = &UnknownEventDetails{Data: pkt.Details}
+		return nil
+	}
+
+	e.Code = code
+	if t := eventDetailTypes[code]; t != nil {
+		schema := formatLayouts[pkt.Header.PacketFormat].eventDetails[code]
+		if schema.size > 0 {
+			details := reflect.New(t)
+			schema.decode(pkt.Details[:], details.UnsafePointer())
+			e.Details = details.Interface().(EventDetails)
+		}
+	}
+
+	return nil
+}
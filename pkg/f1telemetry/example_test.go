@@ -0,0 +1,98 @@
+package f1telemetry_test
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// carTelemetryPacket returns an F1 25 Car Telemetry packet with car 0 at
+// the given speed
+func carTelemetryPacket(speed uint16) []byte {
+	schema, err := f1telemetry.Schema(f1telemetry.PacketFormat2025, f1telemetry.PacketCarTelemetry)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data := make([]byte, schema.Size)
+	binary.LittleEndian.PutUint16(data, f1telemetry.PacketFormat2025)
+	data[6] = uint8(f1telemetry.PacketCarTelemetry)
+	for _, field := range schema.Fields {
+		if field.Name == "CarTelemetryData[0].Speed" {
+			binary.LittleEndian.PutUint16(data[field.Offset:], speed)
+		}
+	}
+	return data
+}
+
+func ExampleDecodePacket() {
+	data := carTelemetryPacket(287)
+
+	packet, err := f1telemetry.DecodePacket(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if p, ok := packet.(*f1telemetry.PacketCarTelemetryData); ok {
+		fmt.Printf("car 0: %d km/h\n", p.CarTelemetryData[0].Speed)
+	}
+	// Output: car 0: 287 km/h
+}
+
+func ExamplePacketCarTelemetryData_UnmarshalBinary() {
+	// Reuse one value for every packet to avoid allocating
+	var p f1telemetry.PacketCarTelemetryData
+	for _, speed := range []uint16{120, 121} {
+		if err := p.UnmarshalBinary(carTelemetryPacket(speed)); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(p.CarTelemetryData[0].Speed)
+	}
+	// Output:
+	// 120
+	// 121
+}
+
+func ExampleReceiver() {
+	recv := f1telemetry.NewReceiver(f1telemetry.ReceiverConfig{
+		Endpoints:  []f1telemetry.Endpoint{{Port: 20777}},
+		BufferSize: 2048,
+		Policy:     f1telemetry.PolicyDropOldest,
+	})
+	if err := recv.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer recv.Stop()
+
+	for packet := range recv.Packets() {
+		fmt.Println(f1telemetry.GetPacketTypeName(packet.Header.PacketID), packet.Origin)
+		packet.Release()
+	}
+}
+
+func ExamplePipeline() {
+	recv := f1telemetry.NewReceiver(f1telemetry.ReceiverConfig{Port: 20777, BufferSize: 2048})
+	if err := recv.Start(); err != nil {
+		log.Fatal(err)
+	}
+	defer recv.Stop()
+
+	tracker := f1telemetry.NewStateTracker()
+	laps := f1telemetry.StageFunc(func(ctx context.Context, packet *f1telemetry.RecordedPacket) error {
+		var p f1telemetry.LapDataPacket
+		if err := p.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		fmt.Println("lap", p.LapData[packet.Header.PlayerCarIndex].CurrentLapNum)
+		return nil
+	})
+
+	pipeline := f1telemetry.NewPipeline()
+	pipeline.Add("state", tracker, f1telemetry.StageOptions{Policy: f1telemetry.PolicyDropOldest})
+	pipeline.Add("laps", laps, f1telemetry.StageOptions{Types: []f1telemetry.PacketType{f1telemetry.PacketLapData}})
+
+	if err := pipeline.Run(context.Background(), recv); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,190 @@
+package f1telemetry
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Packet formats selectable under "UDP Format" in the game's telemetry
+// settings. PacketHeader.PacketFormat carries the one in use.
+const (
+	PacketFormat2023 uint16 = 2023
+	PacketFormat2024 uint16 = 2024
+	PacketFormat2025 uint16 = 2025
+)
+
+// numPacketTypes is the number of packet IDs known to any supported format
+const numPacketTypes = int(PacketLapPositions) + 1
+
+// formatLayout describes how one packet format differs from the others.
+// Decoders branch on format for fields that were added in later years.
+type formatLayout struct {
+	format uint16
+
+	// sizes holds the wire size of each packet ID as given in the spec,
+	// 0 if it is not sent. The compiled schemas must match these.
+	sizes [numPacketTypes]int
+
+	// nameSize is the size of the name field in Participants and Lobby Info
+	nameSize int
+
+	// weatherForecastSamples is the length of the Session forecast array
+	weatherForecastSamples int
+
+	// schemas and eventDetails are compiled from the packet structs at init
+	schemas      [numPacketTypes]*wireSchema
+	eventDetails map[string]*wireSchema
+}
+
+// arrayLen returns the number of array elements sent for an f1:"len=..." tag
+func (l *formatLayout) arrayLen(name string) (int, bool) {
+	switch name {
+	case "name":
+		return l.nameSize, true
+	case "forecast":
+		return l.weatherForecastSamples, true
+	}
+	return 0, false
+}
+
+// formatLayouts is the registry of supported packet formats
+var formatLayouts = map[uint16]*formatLayout{
+	PacketFormat2023: {
+		format: PacketFormat2023,
+		sizes: [numPacketTypes]int{
+			PacketMotion:              MotionPacketSize,
+			PacketSession:             644,
+			PacketLapData:             1131,
+			PacketEvent:               EventPacketSize,
+			PacketParticipants:        1306,
+			PacketCarSetups:           1107,
+			PacketCarTelemetry:        CarTelemetryPacketSize,
+			PacketCarStatus:           CarStatusPacketSize,
+			PacketFinalClassification: 1020,
+			PacketLobbyInfo:           1218,
+			PacketCarDamage:           953,
+			PacketSessionHistory:      SessionHistoryPacketSize,
+			PacketTyreSets:            TyreSetsPacketSize,
+			PacketMotionEx:            217,
+		},
+		nameSize:               48,
+		weatherForecastSamples: 56,
+	},
+	PacketFormat2024: {
+		format: PacketFormat2024,
+		sizes: [numPacketTypes]int{
+			PacketMotion:              MotionPacketSize,
+			PacketSession:             SessionPacketSize,
+			PacketLapData:             LapDataPacketSize,
+			PacketEvent:               EventPacketSize,
+			PacketParticipants:        1350,
+			PacketCarSetups:           CarSetupsPacketSize,
+			PacketCarTelemetry:        CarTelemetryPacketSize,
+			PacketCarStatus:           CarStatusPacketSize,
+			PacketFinalClassification: 1020,
+			PacketLobbyInfo:           1306,
+			PacketCarDamage:           953,
+			PacketSessionHistory:      SessionHistoryPacketSize,
+			PacketTyreSets:            TyreSetsPacketSize,
+			PacketMotionEx:            237,
+			PacketTimeTrial:           TimeTrialPacketSize,
+		},
+		nameSize:               48,
+		weatherForecastSamples: MaxWeatherForecastSamples,
+	},
+	PacketFormat2025: {
+		format: PacketFormat2025,
+		sizes: [numPacketTypes]int{
+			PacketMotion:              MotionPacketSize,
+			PacketSession:             SessionPacketSize,
+			PacketLapData:             LapDataPacketSize,
+			PacketEvent:               EventPacketSize,
+			PacketParticipants:        ParticipantsPacketSize,
+			PacketCarSetups:           CarSetupsPacketSize,
+			PacketCarTelemetry:        CarTelemetryPacketSize,
+			PacketCarStatus:           CarStatusPacketSize,
+			PacketFinalClassification: FinalClassificationPacketSize,
+			PacketLobbyInfo:           LobbyInfoPacketSize,
+			PacketCarDamage:           CarDamagePacketSize,
+			PacketSessionHistory:      SessionHistoryPacketSize,
+			PacketTyreSets:            TyreSetsPacketSize,
+			PacketMotionEx:            MotionExPacketSize,
+			PacketTimeTrial:           TimeTrialPacketSize,
+			PacketLapPositions:        LapPositionsPacketSize,
+		},
+		nameSize:               32,
+		weatherForecastSamples: MaxWeatherForecastSamples,
+	},
+}
+
+// headerSchema is the layout of PacketHeader, which is the same in every format
+var headerSchema = compileSchema(reflect.TypeOf(PacketHeader{}), nil)
+
+// init compiles the packet schemas of every format and panics if any of
+// them disagrees with the sizes in the spec
+func init() {
+	if headerSchema.size != PacketHeaderSize {
+		panic(fmt.Sprintf("f1telemetry: packet header schema is %d bytes, spec says %d",
+			headerSchema.size, PacketHeaderSize))
+	}
+
+	for _, layout := range formatLayouts {
+		for id, size := range layout.sizes {
+			if size == 0 {
+				continue
+			}
+			schema := compileSchema(packetTypes[id], layout)
+			if schema.size != size {
+				panic(fmt.Sprintf("f1telemetry: %s schema for format %d is %d bytes, spec says %d",
+					GetPacketTypeName(uint8(id)), layout.format, schema.size, size))
+			}
+			layout.schemas[id] = schema
+		}
+
+		layout.eventDetails = make(map[string]*wireSchema)
+		for code, t := range eventDetailTypes {
+			if t == nil {
+				continue
+			}
+			schema := compileSchema(t, layout)
+			if schema.size > eventDetailsSize {
+				panic(fmt.Sprintf("f1telemetry: %s event schema for format %d is %d bytes, max %d",
+					code, layout.format, schema.size, eventDetailsSize))
+			}
+			layout.eventDetails[code] = schema
+		}
+	}
+}
+
+// SupportedFormats returns the packet formats that can be decoded, oldest first
+func SupportedFormats() []uint16 {
+	formats := make([]uint16, 0, len(formatLayouts))
+	for f := range formatLayouts {
+		formats = append(formats, f)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// PacketSize returns the wire size of a packet type in the given format
+func PacketSize(format uint16, id PacketType) (int, error) {
+	layout, err := lookupFormat(format)
+	if err != nil {
+		return 0, err
+	}
+	if int(id) >= numPacketTypes || layout.sizes[id] == 0 {
+		return 0, fmt.Errorf("%w: %s packets are not sent in format %d",
+			ErrUnsupportedFormat, GetPacketTypeName(uint8(id)), format)
+	}
+	return layout.sizes[id], nil
+}
+
+// lookupFormat returns the layout registered for a packet format
+func lookupFormat(format uint16) (*formatLayout, error) {
+	layout, ok := formatLayouts[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedFormat, format)
+	}
+	return layout, nil
+}
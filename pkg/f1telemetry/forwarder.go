@@ -0,0 +1,114 @@
+package f1telemetry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+)
+
+// ForwardTarget is a UDP address received packets are re-sent to
+type ForwardTarget struct {
+	Address     string       // host:port
+	PacketTypes []PacketType // Only these types are forwarded, all if empty
+}
+
+// ForwardStats holds the counters of one forward target
+type ForwardStats struct {
+	Target   string
+	Packets  uint64 // Packets sent
+	Bytes    uint64 // Bytes sent
+	Filtered uint64 // Packets skipped by the packet type filter
+	Errors   uint64 // Failed sends
+}
+
+// Forwarder re-sends packets to a list of UDP targets so other telemetry
+// apps keep working while the game sends to this one. Sends are made as
+// packets arrive and never block on a slow target. It is safe for
+// concurrent use.
+type Forwarder struct {
+	targets []*forwardTarget
+}
+
+// forwardTarget is an open ForwardTarget and its counters
+type forwardTarget struct {
+	address string
+	conn    *net.UDPConn
+	types   uint32 // Bit n set if packet ID n is forwarded, 0 for all
+
+	packets  atomic.Uint64
+	bytes    atomic.Uint64
+	filtered atomic.Uint64
+	errors   atomic.Uint64
+}
+
+// NewForwarder opens a socket for each target
+func NewForwarder(targets []ForwardTarget) (*Forwarder, error) {
+	f := &Forwarder{}
+	for _, target := range targets {
+		addr, err := net.ResolveUDPAddr("udp", target.Address)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("invalid forward target %q: %w", target.Address, err)
+		}
+		conn, err := net.DialUDP("udp", nil, addr)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to open forward target %q: %w", target.Address, err)
+		}
+
+		t := &forwardTarget{address: target.Address, conn: conn}
+		for _, id := range target.PacketTypes {
+			t.types |= 1 << id
+		}
+		f.targets = append(f.targets, t)
+	}
+	return f, nil
+}
+
+// Forward sends a packet to every target that accepts its type
+func (f *Forwarder) Forward(packet *RecordedPacket) {
+	bit := uint32(1) << packet.Header.PacketID
+	for _, t := range f.targets {
+		if t.types != 0 && t.types&bit == 0 {
+			t.filtered.Add(1)
+			continue
+		}
+		n, err := t.conn.Write(packet.Data)
+		if err != nil {
+			t.errors.Add(1)
+			continue
+		}
+		t.packets.Add(1)
+		t.bytes.Add(uint64(n))
+	}
+}
+
+// HandlePacket makes the forwarder a pipeline Stage
+func (f *Forwarder) HandlePacket(ctx context.Context, packet *RecordedPacket) error {
+	f.Forward(packet)
+	return nil
+}
+
+// Stats returns the counters of every target in configured order
+func (f *Forwarder) Stats() []ForwardStats {
+	stats := make([]ForwardStats, len(f.targets))
+	for i, t := range f.targets {
+		stats[i] = ForwardStats{
+			Target:   t.address,
+			Packets:  t.packets.Load(),
+			Bytes:    t.bytes.Load(),
+			Filtered: t.filtered.Load(),
+			Errors:   t.errors.Load(),
+		}
+	}
+	return stats
+}
+
+// Close closes the target sockets
+func (f *Forwarder) Close() error {
+	for _, t := range f.targets {
+		t.conn.Close()
+	}
+	return nil
+}
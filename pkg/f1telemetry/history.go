@@ -0,0 +1,104 @@
+package f1telemetry
+
+import "time"
+
+// SessionHistoryPacketSize is the size of a Session History packet (ID 11)
+const SessionHistoryPacketSize = 1460
+
+// MaxLapHistory is the number of lap slots in a Session History packet
+const MaxLapHistory = 100
+
+// Lap validity bit flags used in LapHistoryData.LapValidBitFlags
+const (
+	LapValidFlag     = 0x01
+	Sector1ValidFlag = 0x02
+	Sector2ValidFlag = 0x04
+	Sector3ValidFlag = 0x08
+)
+
+// LapHistoryData holds the timing of one completed or partial lap
+type LapHistoryData struct {
+	LapTimeInMS            uint32 `unit:"ms"`
+	Sector1TimeMSPart      uint16 `unit:"ms"`
+	Sector1TimeMinutesPart uint8  `unit:"min"`
+	Sector2TimeMSPart      uint16 `unit:"ms"`
+	Sector2TimeMinutesPart uint8  `unit:"min"`
+	Sector3TimeMSPart      uint16 `unit:"ms"`
+	Sector3TimeMinutesPart uint8  `unit:"min"`
+	LapValidBitFlags       uint8
+}
+
+// TyreStintHistoryData describes one tyre stint
+type TyreStintHistoryData struct {
+	EndLap             uint8 // 255 for the current tyre
+	TyreActualCompound uint8
+	TyreVisualCompound uint8
+}
+
+// PacketSessionHistoryData is the decoded Session History packet (ID 11).
+// Each packet covers a single car; the game cycles through all cars.
+type PacketSessionHistoryData struct {
+	Header            PacketHeader
+	CarIdx            uint8
+	NumLaps           uint8 // Including the current partial lap
+	NumTyreStints     uint8
+	BestLapTimeLapNum uint8
+	BestSector1LapNum uint8
+	BestSector2LapNum uint8
+	BestSector3LapNum uint8
+	LapHistoryData    [MaxLapHistory]LapHistoryData
+	TyreStintsHistory [MaxTyreStints]TyreStintHistoryData
+}
+
+// LapTime returns the lap time as a duration
+func (l *LapHistoryData) LapTime() time.Duration {
+	return time.Duration(l.LapTimeInMS) * time.Millisecond
+}
+
+// Sector1Time returns the sector 1 time with its minutes part applied
+func (l *LapHistoryData) Sector1Time() time.Duration {
+	return splitTime(l.Sector1TimeMinutesPart, l.Sector1TimeMSPart)
+}
+
+// Sector2Time returns the sector 2 time with its minutes part applied
+func (l *LapHistoryData) Sector2Time() time.Duration {
+	return splitTime(l.Sector2TimeMinutesPart, l.Sector2TimeMSPart)
+}
+
+// Sector3Time returns the sector 3 time with its minutes part applied
+func (l *LapHistoryData) Sector3Time() time.Duration {
+	return splitTime(l.Sector3TimeMinutesPart, l.Sector3TimeMSPart)
+}
+
+// IsValid reports whether the lap was valid
+func (l *LapHistoryData) IsValid() bool {
+	return l.LapValidBitFlags&LapValidFlag != 0
+}
+
+// Laps returns the lap entries that are in use
+func (p *PacketSessionHistoryData) Laps() []LapHistoryData {
+	n := int(p.NumLaps)
+	if n > MaxLapHistory {
+		n = MaxLapHistory
+	}
+	return p.LapHistoryData[:n]
+}
+
+// Stints returns the tyre stint entries that are in use
+func (p *PacketSessionHistoryData) Stints() []TyreStintHistoryData {
+	n := int(p.NumTyreStints)
+	if n > MaxTyreStints {
+		n = MaxTyreStints
+	}
+	return p.TyreStintsHistory[:n]
+}
+
+// ParseSessionHistoryPacket decodes the lap and stint history of one car from packet ID 11
+func ParseSessionHistoryPacket(data []byte) (*PacketSessionHistoryData, error) {
+	return parsePacket[PacketSessionHistoryData](data, PacketSessionHistory)
+}
+
+// UnmarshalBinary decodes a Session History packet (ID 11) into p, reusing its storage
+func (p *PacketSessionHistoryData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketSessionHistory, p)
+}
@@ -0,0 +1,125 @@
+package f1telemetry
+
+import "time"
+
+// LapDataPacketSize is the size of a Lap Data packet (ID 2)
+const LapDataPacketSize = 1285
+
+// LapData holds timing and race state for a single car
+type LapData struct {
+	LastLapTimeInMS              uint32  `unit:"ms"`
+	CurrentLapTimeInMS           uint32  `unit:"ms"`
+	Sector1TimeMSPart            uint16  `unit:"ms"`
+	Sector1TimeMinutesPart       uint8   `unit:"min"`
+	Sector2TimeMSPart            uint16  `unit:"ms"`
+	Sector2TimeMinutesPart       uint8   `unit:"min"`
+	DeltaToCarInFrontMSPart      uint16  `unit:"ms"` // Whole delta in F1 23
+	DeltaToCarInFrontMinutesPart uint8   `f1:"since=2024" unit:"min"`
+	DeltaToRaceLeaderMSPart      uint16  `unit:"ms"` // Whole delta in F1 23
+	DeltaToRaceLeaderMinutesPart uint8   `f1:"since=2024" unit:"min"`
+	LapDistance                  float32 `unit:"m"` // Negative before crossing the line
+	TotalDistance                float32 `unit:"m"` // Negative before crossing the line
+	SafetyCarDelta               float32 `unit:"s"`
+	CarPosition                  uint8
+	CurrentLapNum                uint8
+	PitStatus                    uint8 // 0 = none, 1 = pitting, 2 = in pit area
+	NumPitStops                  uint8
+	Sector                       uint8 // 0 = sector 1, 1 = sector 2, 2 = sector 3
+	CurrentLapInvalid            uint8
+	Penalties                    uint8 `unit:"s"` // Accumulated time penalties
+	TotalWarnings                uint8
+	CornerCuttingWarnings        uint8
+	NumUnservedDriveThroughPens  uint8
+	NumUnservedStopGoPens        uint8
+	GridPosition                 uint8
+	DriverStatus                 uint8 // 0 = garage, 1 = flying lap, 2 = in lap, 3 = out lap, 4 = on track
+	ResultStatus                 uint8 // 0 = invalid, 1 = inactive, 2 = active, 3 = finished ...
+	PitLaneTimerActive           uint8
+	PitLaneTimeInLaneInMS        uint16 `unit:"ms"`
+	PitStopTimerInMS             uint16 `unit:"ms"`
+	PitStopShouldServePen        uint8
+	SpeedTrapFastestSpeed        float32 `f1:"since=2024" unit:"km/h"`
+	SpeedTrapFastestLap          uint8   `f1:"since=2024"` // 255 = not set
+}
+
+// LapDataPacket is the decoded Lap Data packet (ID 2). It is not named
+// PacketLapData because that name is taken by the PacketType constant.
+type LapDataPacket struct {
+	Header               PacketHeader
+	LapData              [MaxCars]LapData
+	TimeTrialPBCarIdx    uint8 // 255 if invalid
+	TimeTrialRivalCarIdx uint8 // 255 if invalid
+}
+
+// LastLapTime returns the last lap time as a duration
+func (l *LapData) LastLapTime() time.Duration {
+	return time.Duration(l.LastLapTimeInMS) * time.Millisecond
+}
+
+// CurrentLapTime returns the current lap time as a duration
+func (l *LapData) CurrentLapTime() time.Duration {
+	return time.Duration(l.CurrentLapTimeInMS) * time.Millisecond
+}
+
+// Sector1Time returns the sector 1 time with its minutes part applied
+func (l *LapData) Sector1Time() time.Duration {
+	return splitTime(l.Sector1TimeMinutesPart, l.Sector1TimeMSPart)
+}
+
+// Sector2Time returns the sector 2 time with its minutes part applied
+func (l *LapData) Sector2Time() time.Duration {
+	return splitTime(l.Sector2TimeMinutesPart, l.Sector2TimeMSPart)
+}
+
+// DeltaToCarInFront returns the gap to the car ahead
+func (l *LapData) DeltaToCarInFront() time.Duration {
+	return splitTime(l.DeltaToCarInFrontMinutesPart, l.DeltaToCarInFrontMSPart)
+}
+
+// DeltaToRaceLeader returns the gap to the race leader
+func (l *LapData) DeltaToRaceLeader() time.Duration {
+	return splitTime(l.DeltaToRaceLeaderMinutesPart, l.DeltaToRaceLeaderMSPart)
+}
+
+// splitTime joins a minutes part and a milliseconds part into a duration
+func splitTime(minutes uint8, ms uint16) time.Duration {
+	return time.Duration(minutes)*time.Minute + time.Duration(ms)*time.Millisecond
+}
+
+// TelemetryData returns the display values for one car, or nil if the index is out of range
+func (p *LapDataPacket) TelemetryData(carIndex uint8) *TelemetryData {
+	t := &TelemetryData{}
+	if !p.UpdateTelemetryData(carIndex, t) {
+		return nil
+	}
+	return t
+}
+
+// UpdateTelemetryData copies the display values this packet carries for
+// one car into t, leaving the other fields alone. It reports false if the
+// index is out of range.
+func (p *LapDataPacket) UpdateTelemetryData(carIndex uint8, t *TelemetryData) bool {
+	if int(carIndex) >= MaxCars {
+		return false
+	}
+	p.LapData[carIndex].updateTelemetryData(t)
+	return true
+}
+
+// updateTelemetryData copies the display values of the car into t
+func (l *LapData) updateTelemetryData(t *TelemetryData) {
+	t.CurrentLapNum = l.CurrentLapNum
+	t.CarPosition = l.CarPosition
+	t.CurrentLapTime = l.CurrentLapTime()
+	t.LastLapTime = l.LastLapTime()
+}
+
+// ParseLapDataPacket decodes lap data for all cars from packet ID 2
+func ParseLapDataPacket(data []byte) (*LapDataPacket, error) {
+	return parsePacket[LapDataPacket](data, PacketLapData)
+}
+
+// UnmarshalBinary decodes a Lap Data packet (ID 2) into p, reusing its storage
+func (p *LapDataPacket) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketLapData, p)
+}
@@ -0,0 +1,71 @@
+package f1telemetry
+
+// LapPositionsPacketSize is the size of a Lap Positions packet (ID 15)
+const LapPositionsPacketSize = 1131
+
+// MaxLapPositionsPerPacket is the number of laps carried in one Lap Positions packet
+const MaxLapPositionsPerPacket = 50
+
+// PacketLapPositionsData is the decoded Lap Positions packet (ID 15).
+// Races longer than 50 laps are split over two packets with different
+// LapStart values; use LapPositionHistory to merge them.
+type PacketLapPositionsData struct {
+	Header   PacketHeader
+	NumLaps  uint8
+	LapStart uint8 // Index of the first lap in this packet, 0 indexed
+
+	// PositionForVehicleIdx holds the position of each car at the start
+	// of each lap, 0 if there is no record
+	PositionForVehicleIdx [MaxLapPositionsPerPacket][MaxCars]uint8
+}
+
+// ParseLapPositionsPacket decodes a Lap Positions packet (ID 15)
+func ParseLapPositionsPacket(data []byte) (*PacketLapPositionsData, error) {
+	return parsePacket[PacketLapPositionsData](data, PacketLapPositions)
+}
+
+// UnmarshalBinary decodes a Lap Positions packet (ID 15) into p, reusing its storage
+func (p *PacketLapPositionsData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketLapPositions, p)
+}
+
+// LapPositionHistory merges Lap Positions packets into the full position
+// history of a session
+type LapPositionHistory struct {
+	laps [][MaxCars]uint8
+}
+
+// Add merges the laps carried by a Lap Positions packet
+func (h *LapPositionHistory) Add(pkt *PacketLapPositionsData) {
+	n := int(pkt.NumLaps)
+	if n > MaxLapPositionsPerPacket {
+		n = MaxLapPositionsPerPacket
+	}
+
+	end := int(pkt.LapStart) + n
+	for len(h.laps) < end {
+		h.laps = append(h.laps, [MaxCars]uint8{})
+	}
+	copy(h.laps[pkt.LapStart:end], pkt.PositionForVehicleIdx[:n])
+}
+
+// NumLaps returns the number of laps in the history
+func (h *LapPositionHistory) NumLaps() int {
+	return len(h.laps)
+}
+
+// Position returns the position of a car at the start of a lap (0 indexed),
+// or 0 if there is no record
+func (h *LapPositionHistory) Position(lap int, carIdx uint8) uint8 {
+	if lap < 0 || lap >= len(h.laps) || int(carIdx) >= MaxCars {
+		return 0
+	}
+	return h.laps[lap][carIdx]
+}
+
+// Positions returns a copy of the merged history, indexed by lap then car
+func (h *LapPositionHistory) Positions() [][MaxCars]uint8 {
+	out := make([][MaxCars]uint8, len(h.laps))
+	copy(out, h.laps)
+	return out
+}
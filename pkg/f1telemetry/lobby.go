@@ -0,0 +1,49 @@
+package f1telemetry
+
+// LobbyInfoPacketSize is the size of a Lobby Info packet (ID 9)
+const LobbyInfoPacketSize = 954
+
+// LobbyInfoData describes a single player in a multiplayer lobby
+type LobbyInfoData struct {
+	AIControlled    uint8 // 1 = AI, 0 = human
+	TeamID          uint8 // 255 if no team selected
+	Nationality     uint8
+	Platform        uint8             // 1 = Steam, 3 = PlayStation, 4 = Xbox, 6 = Origin, 255 = unknown
+	Name            [MaxNameSize]byte `f1:"len=name"` // UTF-8, null terminated
+	CarNumber       uint8
+	YourTelemetry   uint8  `f1:"since=2024"` // 0 = restricted, 1 = public
+	ShowOnlineNames uint8  `f1:"since=2024"`
+	TechLevel       uint16 `f1:"since=2024"` // F1 World tech level
+	ReadyStatus     uint8  // 0 = not ready, 1 = ready, 2 = spectating
+}
+
+// PacketLobbyInfoData is the decoded Lobby Info packet (ID 9)
+type PacketLobbyInfoData struct {
+	Header       PacketHeader
+	NumPlayers   uint8
+	LobbyPlayers [MaxCars]LobbyInfoData
+}
+
+// NameString returns the player name up to the null terminator
+func (l *LobbyInfoData) NameString() string {
+	return nullTerminatedString(l.Name[:])
+}
+
+// Players returns the lobby entries that are in use
+func (p *PacketLobbyInfoData) Players() []LobbyInfoData {
+	n := int(p.NumPlayers)
+	if n > MaxCars {
+		n = MaxCars
+	}
+	return p.LobbyPlayers[:n]
+}
+
+// ParseLobbyInfoPacket decodes lobby members from packet ID 9
+func ParseLobbyInfoPacket(data []byte) (*PacketLobbyInfoData, error) {
+	return parsePacket[PacketLobbyInfoData](data, PacketLobbyInfo)
+}
+
+// UnmarshalBinary decodes a Lobby Info packet (ID 9) into p, reusing its storage
+func (p *PacketLobbyInfoData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketLobbyInfo, p)
+}
@@ -0,0 +1,59 @@
+package f1telemetry
+
+// MotionPacketSize is the size of a Motion packet (ID 0)
+const MotionPacketSize = 1349
+
+// normalisedVectorScale converts packed int16 direction values to -1.0..1.0
+const normalisedVectorScale = 32767.0
+
+// CarMotionData holds physics data for a single car
+type CarMotionData struct {
+	WorldPositionX     float32 `unit:"m"`
+	WorldPositionY     float32 `unit:"m"`
+	WorldPositionZ     float32 `unit:"m"`
+	WorldVelocityX     float32 `unit:"m/s"`
+	WorldVelocityY     float32 `unit:"m/s"`
+	WorldVelocityZ     float32 `unit:"m/s"`
+	WorldForwardDirX   int16   // Normalised forward direction, see ForwardDir
+	WorldForwardDirY   int16
+	WorldForwardDirZ   int16
+	WorldRightDirX     int16 // Normalised right direction, see RightDir
+	WorldRightDirY     int16
+	WorldRightDirZ     int16
+	GForceLateral      float32 `unit:"g"`
+	GForceLongitudinal float32 `unit:"g"`
+	GForceVertical     float32 `unit:"g"`
+	Yaw                float32 `unit:"rad"`
+	Pitch              float32 `unit:"rad"`
+	Roll               float32 `unit:"rad"`
+}
+
+// PacketMotionData is the decoded Motion packet (ID 0)
+type PacketMotionData struct {
+	Header        PacketHeader
+	CarMotionData [MaxCars]CarMotionData
+}
+
+// ForwardDir returns the forward direction vector as floats
+func (c *CarMotionData) ForwardDir() (x, y, z float32) {
+	return float32(c.WorldForwardDirX) / normalisedVectorScale,
+		float32(c.WorldForwardDirY) / normalisedVectorScale,
+		float32(c.WorldForwardDirZ) / normalisedVectorScale
+}
+
+// RightDir returns the right direction vector as floats
+func (c *CarMotionData) RightDir() (x, y, z float32) {
+	return float32(c.WorldRightDirX) / normalisedVectorScale,
+		float32(c.WorldRightDirY) / normalisedVectorScale,
+		float32(c.WorldRightDirZ) / normalisedVectorScale
+}
+
+// ParseMotionPacket decodes motion data for all cars from packet ID 0
+func ParseMotionPacket(data []byte) (*PacketMotionData, error) {
+	return parsePacket[PacketMotionData](data, PacketMotion)
+}
+
+// UnmarshalBinary decodes a Motion packet (ID 0) into p, reusing its storage
+func (p *PacketMotionData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketMotion, p)
+}
@@ -0,0 +1,53 @@
+package f1telemetry
+
+// MotionExPacketSize is the size of a Motion Ex packet (ID 13)
+const MotionExPacketSize = 273
+
+// PacketMotionExData is the decoded Motion Ex packet (ID 13). It only
+// covers the player car. Wheel arrays are ordered RL, RR, FL, FR.
+type PacketMotionExData struct {
+	Header PacketHeader
+
+	SuspensionPosition     [4]float32
+	SuspensionVelocity     [4]float32
+	SuspensionAcceleration [4]float32
+	WheelSpeed             [4]float32
+	WheelSlipRatio         [4]float32
+	WheelSlipAngle         [4]float32
+	WheelLatForce          [4]float32
+	WheelLongForce         [4]float32
+	HeightOfCOGAboveGround float32
+	LocalVelocityX         float32 `unit:"m/s"`
+	LocalVelocityY         float32 `unit:"m/s"`
+	LocalVelocityZ         float32 `unit:"m/s"`
+	AngularVelocityX       float32 `unit:"rad/s"`
+	AngularVelocityY       float32 `unit:"rad/s"`
+	AngularVelocityZ       float32 `unit:"rad/s"`
+	AngularAccelerationX   float32 `unit:"rad/s/s"`
+	AngularAccelerationY   float32 `unit:"rad/s/s"`
+	AngularAccelerationZ   float32 `unit:"rad/s/s"`
+	FrontWheelsAngle       float32 `unit:"rad"`
+	WheelVertForce         [4]float32
+
+	// Added in F1 24
+	FrontAeroHeight float32 `f1:"since=2024" unit:"m"` // Front plank edge height above road
+	RearAeroHeight  float32 `f1:"since=2024" unit:"m"` // Rear plank edge height above road
+	FrontRollAngle  float32 `f1:"since=2024" unit:"rad"`
+	RearRollAngle   float32 `f1:"since=2024" unit:"rad"`
+	ChassisYaw      float32 `f1:"since=2024" unit:"rad"` // Relative to direction of motion
+
+	// Added in F1 25
+	ChassisPitch    float32    `f1:"since=2025" unit:"rad"` // Relative to direction of motion
+	WheelCamber     [4]float32 `f1:"since=2025" unit:"rad"`
+	WheelCamberGain [4]float32 `f1:"since=2025" unit:"rad"`
+}
+
+// ParseMotionExPacket decodes extended player motion from packet ID 13
+func ParseMotionExPacket(data []byte) (*PacketMotionExData, error) {
+	return parsePacket[PacketMotionExData](data, PacketMotionEx)
+}
+
+// UnmarshalBinary decodes a Motion Ex packet (ID 13) into p, reusing its storage
+func (p *PacketMotionExData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketMotionEx, p)
+}
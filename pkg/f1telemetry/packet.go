@@ -0,0 +1,132 @@
+package f1telemetry
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// PacketType represents different F1 telemetry packet types
+type PacketType uint8
+
+const (
+	PacketMotion PacketType = iota
+	PacketSession
+	PacketLapData
+	PacketEvent
+	PacketParticipants
+	PacketCarSetups
+	PacketCarTelemetry
+	PacketCarStatus
+	PacketFinalClassification
+	PacketLobbyInfo
+	PacketCarDamage
+	PacketSessionHistory
+	PacketTyreSets
+	PacketMotionEx
+	PacketTimeTrial
+	PacketLapPositions
+)
+
+const (
+	// PacketHeaderSize is the size of PacketHeader on the wire
+	PacketHeaderSize = 29
+
+	// MaxCars is the number of car slots in every per-car array
+	MaxCars = 22
+)
+
+// PacketHeader is the common header for all F1 telemetry packets
+type PacketHeader struct {
+	PacketFormat            uint16
+	GameYear                uint8
+	GameMajorVersion        uint8
+	GameMinorVersion        uint8
+	PacketVersion           uint8
+	PacketID                uint8
+	SessionUID              uint64
+	SessionTime             float32 `unit:"s"`
+	FrameIdentifier         uint32
+	OverallFrameIdentifier  uint32
+	PlayerCarIndex          uint8
+	SecondaryPlayerCarIndex uint8
+}
+
+// RecordedPacket represents a packet with timestamp for recording/playback
+type RecordedPacket struct {
+	Timestamp time.Time
+	Data      []byte
+	Header    PacketHeader
+	Origin    Origin // Where the packet was received, zero if unknown
+
+	pool *packetPool // Set for packets handed out by Receiver
+	refs int32       // References held beyond the first, see Retain
+}
+
+// Retain adds a reference to a packet from Receiver so it can be shared by
+// several consumers. Each Retain needs a matching Release.
+func (p *RecordedPacket) Retain() {
+	if p.pool != nil {
+		atomic.AddInt32(&p.refs, 1)
+	}
+}
+
+// Release hands a packet from Receiver back for reuse once the consumer is
+// done with it. Neither the packet nor its Data may be used afterwards.
+// Releasing is optional and does nothing for packets from other sources.
+func (p *RecordedPacket) Release() {
+	if p.pool == nil || atomic.AddInt32(&p.refs, -1) >= 0 {
+		return
+	}
+	p.refs = 0
+	p.pool.put(p)
+}
+
+// ParseHeader extracts the packet header from raw data
+func ParseHeader(data []byte) (*PacketHeader, error) {
+	if len(data) < PacketHeaderSize {
+		return nil, ErrInvalidPacket
+	}
+
+	header := &PacketHeader{}
+	headerSchema.decode(data, unsafe.Pointer(header))
+
+	return header, nil
+}
+
+// UnmarshalBinary decodes the packet header at the start of data into h
+func (h *PacketHeader) UnmarshalBinary(data []byte) error {
+	if len(data) < PacketHeaderSize {
+		return ErrInvalidPacket
+	}
+	headerSchema.decode(data, unsafe.Pointer(h))
+	return nil
+}
+
+// packetTypeNames holds the human-readable name of each packet ID
+var packetTypeNames = [numPacketTypes]string{
+	PacketMotion:              "Motion",
+	PacketSession:             "Session",
+	PacketLapData:             "Lap Data",
+	PacketEvent:               "Event",
+	PacketParticipants:        "Participants",
+	PacketCarSetups:           "Car Setups",
+	PacketCarTelemetry:        "Car Telemetry",
+	PacketCarStatus:           "Car Status",
+	PacketFinalClassification: "Final Classification",
+	PacketLobbyInfo:           "Lobby Info",
+	PacketCarDamage:           "Car Damage",
+	PacketSessionHistory:      "Session History",
+	PacketTyreSets:            "Tyre Sets",
+	PacketMotionEx:            "Motion Ex",
+	PacketTimeTrial:           "Time Trial",
+	PacketLapPositions:        "Lap Positions",
+}
+
+// GetPacketTypeName returns human-readable packet type name
+func GetPacketTypeName(packetID uint8) string {
+	if int(packetID) < numPacketTypes {
+		return packetTypeNames[packetID]
+	}
+	return "Unknown"
+}
@@ -0,0 +1,179 @@
+package f1telemetry
+
+import "time"
+
+const (
+	// CarTelemetryPacketSize is the size of a Car Telemetry packet (ID 6)
+	CarTelemetryPacketSize = 1352
+
+	// CarStatusPacketSize is the size of a Car Status packet (ID 7)
+	CarStatusPacketSize = 1239
+)
+
+// TelemetryData holds the player car values shown in the live display.
+// It is derived from the full per-car packet structs, see CarState.
+type TelemetryData struct {
+	Speed          float32
+	Throttle       float32
+	Brake          float32
+	Gear           int8
+	EngineRPM      uint16
+	DRS            uint8 // 0 = off, 1 = on
+	DRSAllowed     uint8 // 0 = not allowed, 1 = allowed
+	EngineTemp     uint16
+	TyreTemp       [4]uint8 // RL, RR, FL, FR
+	TyrePressure   [4]float32
+	FuelLevel      float32
+	ERSStoreEnergy float32
+	ERSDeployMode  uint8
+	CurrentLapNum  uint8
+	CarPosition    uint8
+	CurrentLapTime time.Duration
+	LastLapTime    time.Duration
+}
+
+// CarTelemetryData holds telemetry for a single car. Wheel arrays are
+// ordered RL, RR, FL, FR.
+type CarTelemetryData struct {
+	Speed                   uint16     `unit:"km/h"`
+	Throttle                float32    // 0.0 to 1.0
+	Steer                   float32    // -1.0 (full left) to 1.0 (full right)
+	Brake                   float32    // 0.0 to 1.0
+	Clutch                  uint8      `unit:"%"`
+	Gear                    int8       // 1-8, N = 0, R = -1
+	EngineRPM               uint16     `unit:"rpm"`
+	DRS                     uint8      // 0 = off, 1 = on
+	RevLightsPercent        uint8      `unit:"%"`
+	RevLightsBitValue       uint16     // Bit 0 = leftmost LED, bit 14 = rightmost LED
+	BrakesTemperature       [4]uint16  `unit:"°C"`
+	TyresSurfaceTemperature [4]uint8   `unit:"°C"`
+	TyresInnerTemperature   [4]uint8   `unit:"°C"`
+	EngineTemperature       uint16     `unit:"°C"`
+	TyresPressure           [4]float32 `unit:"psi"`
+	SurfaceType             [4]uint8
+}
+
+// PacketCarTelemetryData is the decoded Car Telemetry packet (ID 6)
+type PacketCarTelemetryData struct {
+	Header                       PacketHeader
+	CarTelemetryData             [MaxCars]CarTelemetryData
+	MFDPanelIndex                uint8 // 255 = MFD closed
+	MFDPanelIndexSecondaryPlayer uint8
+	SuggestedGear                int8 // 0 if no gear suggested
+}
+
+// CarStatusData holds status for a single car
+type CarStatusData struct {
+	TractionControl         uint8 // 0 = off, 1 = medium, 2 = full
+	AntiLockBrakes          uint8
+	FuelMix                 uint8 // 0 = lean, 1 = standard, 2 = rich, 3 = max
+	FrontBrakeBias          uint8 `unit:"%"`
+	PitLimiterStatus        uint8
+	FuelInTank              float32 `unit:"kg"`
+	FuelCapacity            float32 `unit:"kg"`
+	FuelRemainingLaps       float32 `unit:"laps"`
+	MaxRPM                  uint16  `unit:"rpm"`
+	IdleRPM                 uint16  `unit:"rpm"`
+	MaxGears                uint8
+	DRSAllowed              uint8
+	DRSActivationDistance   uint16 `unit:"m"` // Until DRS is available, 0 = not available
+	ActualTyreCompound      uint8
+	VisualTyreCompound      uint8
+	TyresAgeLaps            uint8   `unit:"laps"`
+	VehicleFIAFlags         int8    // -1 = unknown, 0 = none, 1 = green, 2 = blue, 3 = yellow
+	EnginePowerICE          float32 `unit:"W"`
+	EnginePowerMGUK         float32 `unit:"W"`
+	ERSStoreEnergy          float32 `unit:"J"`
+	ERSDeployMode           uint8   // 0 = none, 1 = medium, 2 = hotlap, 3 = overtake
+	ERSHarvestedThisLapMGUK float32 `unit:"J"`
+	ERSHarvestedThisLapMGUH float32 `unit:"J"`
+	ERSDeployedThisLap      float32 `unit:"J"`
+	NetworkPaused           uint8
+}
+
+// PacketCarStatusData is the decoded Car Status packet (ID 7)
+type PacketCarStatusData struct {
+	Header        PacketHeader
+	CarStatusData [MaxCars]CarStatusData
+}
+
+// ParseCarTelemetryPacket decodes telemetry for all cars from packet ID 6
+func ParseCarTelemetryPacket(data []byte) (*PacketCarTelemetryData, error) {
+	return parsePacket[PacketCarTelemetryData](data, PacketCarTelemetry)
+}
+
+// UnmarshalBinary decodes a Car Telemetry packet (ID 6) into p, reusing its storage
+func (p *PacketCarTelemetryData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketCarTelemetry, p)
+}
+
+// TelemetryData returns the display values for one car, or nil if the index is out of range
+func (p *PacketCarTelemetryData) TelemetryData(carIndex uint8) *TelemetryData {
+	t := &TelemetryData{}
+	if !p.UpdateTelemetryData(carIndex, t) {
+		return nil
+	}
+	return t
+}
+
+// UpdateTelemetryData copies the display values this packet carries for
+// one car into t, leaving the other fields alone. It reports false if the
+// index is out of range.
+func (p *PacketCarTelemetryData) UpdateTelemetryData(carIndex uint8, t *TelemetryData) bool {
+	if int(carIndex) >= MaxCars {
+		return false
+	}
+	p.CarTelemetryData[carIndex].updateTelemetryData(t)
+	return true
+}
+
+// updateTelemetryData copies the display values of the car into t
+func (c *CarTelemetryData) updateTelemetryData(t *TelemetryData) {
+	t.Speed = float32(c.Speed)
+	t.Throttle = c.Throttle
+	t.Brake = c.Brake
+	t.Gear = c.Gear
+	t.EngineRPM = c.EngineRPM
+	t.DRS = c.DRS
+	t.EngineTemp = c.EngineTemperature
+	t.TyreTemp = c.TyresSurfaceTemperature
+	t.TyrePressure = c.TyresPressure
+}
+
+// ParseCarStatusPacket decodes status for all cars from packet ID 7
+func ParseCarStatusPacket(data []byte) (*PacketCarStatusData, error) {
+	return parsePacket[PacketCarStatusData](data, PacketCarStatus)
+}
+
+// UnmarshalBinary decodes a Car Status packet (ID 7) into p, reusing its storage
+func (p *PacketCarStatusData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketCarStatus, p)
+}
+
+// TelemetryData returns the display values for one car, or nil if the index is out of range
+func (p *PacketCarStatusData) TelemetryData(carIndex uint8) *TelemetryData {
+	t := &TelemetryData{}
+	if !p.UpdateTelemetryData(carIndex, t) {
+		return nil
+	}
+	return t
+}
+
+// UpdateTelemetryData copies the display values this packet carries for
+// one car into t, leaving the other fields alone. It reports false if the
+// index is out of range.
+func (p *PacketCarStatusData) UpdateTelemetryData(carIndex uint8, t *TelemetryData) bool {
+	if int(carIndex) >= MaxCars {
+		return false
+	}
+	p.CarStatusData[carIndex].updateTelemetryData(t)
+	return true
+}
+
+// updateTelemetryData copies the display values of the car into t
+func (c *CarStatusData) updateTelemetryData(t *TelemetryData) {
+	t.FuelLevel = c.FuelInTank
+	t.DRSAllowed = c.DRSAllowed
+	t.ERSStoreEnergy = c.ERSStoreEnergy
+	t.ERSDeployMode = c.ERSDeployMode
+}
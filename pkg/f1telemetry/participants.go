@@ -0,0 +1,70 @@
+package f1telemetry
+
+// ParticipantsPacketSize is the size of a Participants packet (ID 4)
+const ParticipantsPacketSize = 1284
+
+const (
+	// MaxNameSize is the size of the largest name field. Names are 48
+	// bytes up to F1 24 and 32 bytes from F1 25.
+	MaxNameSize = 48
+
+	// MaxLiveryColours is the number of livery colour slots per car
+	MaxLiveryColours = 4
+)
+
+// LiveryColour is an RGB colour of a car livery
+type LiveryColour struct {
+	Red   uint8
+	Green uint8
+	Blue  uint8
+}
+
+// ParticipantData describes a single participant in the session
+type ParticipantData struct {
+	AIControlled    uint8 // 1 = AI, 0 = human
+	DriverID        uint8 // 255 if network human
+	NetworkID       uint8
+	TeamID          uint8
+	MyTeam          uint8 // 1 = My Team
+	RaceNumber      uint8
+	Nationality     uint8
+	Name            [MaxNameSize]byte `f1:"len=name"` // UTF-8, null terminated
+	YourTelemetry   uint8             // 0 = restricted, 1 = public
+	ShowOnlineNames uint8
+	TechLevel       uint16                         `f1:"since=2024"` // F1 World tech level
+	Platform        uint8                          // 1 = Steam, 3 = PlayStation, 4 = Xbox, 6 = Origin, 255 = unknown
+	NumColours      uint8                          `f1:"since=2025"`
+	LiveryColours   [MaxLiveryColours]LiveryColour `f1:"since=2025"`
+}
+
+// PacketParticipantsData is the decoded Participants packet (ID 4)
+type PacketParticipantsData struct {
+	Header        PacketHeader
+	NumActiveCars uint8
+	Participants  [MaxCars]ParticipantData
+}
+
+// NameString returns the participant name up to the null terminator
+func (p *ParticipantData) NameString() string {
+	return nullTerminatedString(p.Name[:])
+}
+
+// ParseParticipantsPacket decodes all participants from packet ID 4
+func ParseParticipantsPacket(data []byte) (*PacketParticipantsData, error) {
+	return parsePacket[PacketParticipantsData](data, PacketParticipants)
+}
+
+// UnmarshalBinary decodes a Participants packet (ID 4) into p, reusing its storage
+func (p *PacketParticipantsData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketParticipants, p)
+}
+
+// nullTerminatedString returns the bytes up to the first null as a string
+func nullTerminatedString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,258 @@
+package f1telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Source produces packets for a Pipeline. Receiver is a source, and so is
+// the player that replays recordings, so the same stages work live and on
+// replay. The channel is closed when the source stops.
+type Source interface {
+	Packets() <-chan *RecordedPacket
+}
+
+// Stage processes packets in a Pipeline. A stage must not keep the packet
+// or its Data after HandlePacket returns; it copies what it needs.
+type Stage interface {
+	HandlePacket(ctx context.Context, packet *RecordedPacket) error
+}
+
+// StageFunc adapts a function to a Stage
+type StageFunc func(ctx context.Context, packet *RecordedPacket) error
+
+// HandlePacket calls f
+func (f StageFunc) HandlePacket(ctx context.Context, packet *RecordedPacket) error {
+	return f(ctx, packet)
+}
+
+// StageOptions configures the buffer in front of a stage
+type StageOptions struct {
+	Buffer int                // Packets buffered for the stage, DefaultQueueDepth if zero
+	Policy BackpressurePolicy // When the buffer is full, PolicyBlock if empty; spilling is not supported
+	Types  []PacketType       // Only these packet types reach the stage, all if empty
+}
+
+// StageStats holds the counters of one pipeline stage
+type StageStats struct {
+	Name      string
+	Handled   uint64 // Packets passed to the stage
+	Dropped   uint64 // Packets dropped because the stage fell behind
+	Errors    uint64 // Packets the stage returned an error for
+	Queued    int    // Packets waiting in the stage buffer
+	LastError error
+}
+
+// Pipeline passes packets from a source to a set of stages. Every stage
+// runs in its own goroutine behind its own buffer, so a slow stage only
+// holds up the others if its policy is PolicyBlock. Stage errors are
+// counted in Stats and do not stop the pipeline.
+type Pipeline struct {
+	mu      sync.Mutex
+	stages  []*pipelineStage
+	running bool
+}
+
+// pipelineStage is a registered stage and its buffer
+type pipelineStage struct {
+	name   string
+	stage  Stage
+	policy BackpressurePolicy
+	buffer int
+	types  uint32 // Bit n set if packet ID n reaches the stage, 0 for all
+	queue  chan *RecordedPacket
+
+	handled atomic.Uint64
+	dropped atomic.Uint64
+	errors  atomic.Uint64
+	lastErr atomic.Value // errorValue
+}
+
+// errorValue wraps an error so atomic.Value always stores one type
+type errorValue struct{ err error }
+
+// NewPipeline creates an empty pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Add registers a stage. Stages cannot be added while the pipeline runs.
+func (p *Pipeline) Add(name string, stage Stage, opts StageOptions) error {
+	policy := opts.Policy
+	if policy == "" {
+		policy = PolicyBlock
+	}
+	if _, err := ParseBackpressurePolicy(string(policy)); err != nil {
+		return err
+	}
+	if policy == PolicySpill {
+		return fmt.Errorf("stage %s: policy %s is not supported by pipeline stages", name, policy)
+	}
+
+	s := &pipelineStage{
+		name:   name,
+		stage:  stage,
+		policy: policy,
+		buffer: opts.Buffer,
+	}
+	if s.buffer <= 0 {
+		s.buffer = DefaultQueueDepth
+	}
+	for _, id := range opts.Types {
+		s.types |= 1 << id
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running {
+		return fmt.Errorf("stage %s: pipeline already running", name)
+	}
+	p.stages = append(p.stages, s)
+	return nil
+}
+
+// Run passes packets from src to the stages until src closes its channel
+// or ctx is cancelled. When src closes, the stages finish what is buffered
+// and Run returns nil; when ctx is cancelled, buffered packets are
+// discarded and Run returns ctx.Err(). Stopping src is up to the caller.
+func (p *Pipeline) Run(ctx context.Context, src Source) error {
+	p.mu.Lock()
+	if p.running {
+		p.mu.Unlock()
+		return fmt.Errorf("pipeline already running")
+	}
+	p.running = true
+	stages := p.stages
+	for _, s := range stages {
+		s.queue = make(chan *RecordedPacket, s.buffer)
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range stages {
+		wg.Add(1)
+		go s.run(ctx, &wg)
+	}
+
+	err := p.dispatch(ctx, src, stages)
+
+	for _, s := range stages {
+		close(s.queue)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.running = false
+	p.mu.Unlock()
+	return err
+}
+
+// Stats returns the counters of every stage in the order they were added
+func (p *Pipeline) Stats() []StageStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]StageStats, len(p.stages))
+	for i, s := range p.stages {
+		stats[i] = StageStats{
+			Name:    s.name,
+			Handled: s.handled.Load(),
+			Dropped: s.dropped.Load(),
+			Errors:  s.errors.Load(),
+			Queued:  len(s.queue),
+		}
+		if v, ok := s.lastErr.Load().(errorValue); ok {
+			stats[i].LastError = v.err
+		}
+	}
+	return stats
+}
+
+// dispatch reads packets from src and hands each to the stages that take it
+func (p *Pipeline) dispatch(ctx context.Context, src Source, stages []*pipelineStage) error {
+	packets := src.Packets()
+	targets := make([]*pipelineStage, 0, len(stages))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case packet, ok := <-packets:
+			if !ok {
+				return nil
+			}
+
+			targets = targets[:0]
+			bit := uint32(1) << packet.Header.PacketID
+			for _, s := range stages {
+				if s.types == 0 || s.types&bit != 0 {
+					targets = append(targets, s)
+				}
+			}
+			if len(targets) == 0 {
+				packet.Release()
+				continue
+			}
+
+			// One reference per stage, each stage releases its own
+			for range targets[1:] {
+				packet.Retain()
+			}
+			for _, s := range targets {
+				s.enqueue(ctx, packet)
+			}
+		}
+	}
+}
+
+// enqueue puts a packet in the stage buffer, applying the stage policy if
+// it is full
+func (s *pipelineStage) enqueue(ctx context.Context, packet *RecordedPacket) {
+	select {
+	case s.queue <- packet:
+		return
+	default:
+	}
+
+	switch s.policy {
+	case PolicyDropOldest:
+		for {
+			select {
+			case s.queue <- packet:
+				return
+			case old := <-s.queue:
+				s.dropped.Add(1)
+				old.Release()
+			}
+		}
+	case PolicyDropNewest:
+		s.dropped.Add(1)
+		packet.Release()
+	default:
+		select {
+		case s.queue <- packet:
+		case <-ctx.Done():
+			packet.Release()
+		}
+	}
+}
+
+// run handles the packets in the stage buffer until it is closed
+func (s *pipelineStage) run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for packet := range s.queue {
+		if ctx.Err() != nil {
+			packet.Release()
+			continue
+		}
+		s.handled.Add(1)
+		if err := s.stage.HandlePacket(ctx, packet); err != nil {
+			s.errors.Add(1)
+			s.lastErr.Store(errorValue{err})
+		}
+		packet.Release()
+	}
+}
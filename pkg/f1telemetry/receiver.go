@@ -0,0 +1,504 @@
+package f1telemetry
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Receiver handles UDP telemetry data reception on one or more endpoints
+type Receiver struct {
+	config   ReceiverConfig
+	conns    []*net.UDPConn
+	packets  chan *RecordedPacket
+	pool     *packetPool
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	control  sync.Mutex // Held by Start and Stop so they do not overlap
+	running  bool
+	stopped  bool // Stop closed packets, Start makes a new channel
+	stats    ReceiverStats
+	sequence map[Origin]*SequenceTracker // Each sender is sequenced on its own
+	spill    *spillRing                  // Set with PolicySpill while running
+	flush    chan struct{}               // Closed by Stop to drain the spill ring
+	drained  chan struct{}               // Closed once the spill ring is drained
+	forward  *Forwarder                  // Set while running if targets are configured
+}
+
+// BackpressurePolicy decides what the receiver does with a packet when
+// the consumer has fallen behind and the packet channel is full
+type BackpressurePolicy string
+
+const (
+	// PolicyDropNewest drops the packet that just arrived
+	PolicyDropNewest BackpressurePolicy = "drop-newest"
+	// PolicyDropOldest drops the oldest queued packet to make room, so
+	// live displays stay current
+	PolicyDropOldest BackpressurePolicy = "drop-oldest"
+	// PolicyBlock stops reading the socket until there is room. Packets
+	// are then lost in the kernel socket buffer if it overflows.
+	PolicyBlock BackpressurePolicy = "block"
+	// PolicySpill queues packets in a ring file on disk until there is
+	// room, dropping the oldest spilled packets if the ring fills up
+	PolicySpill BackpressurePolicy = "spill"
+)
+
+// DefaultQueueDepth is the packet channel capacity when none is configured
+const DefaultQueueDepth = 100
+
+// BackpressurePolicies lists the supported policies
+func BackpressurePolicies() []BackpressurePolicy {
+	return []BackpressurePolicy{PolicyDropNewest, PolicyDropOldest, PolicyBlock, PolicySpill}
+}
+
+// ParseBackpressurePolicy returns the policy with the given name. An
+// empty name selects PolicyDropNewest.
+func ParseBackpressurePolicy(name string) (BackpressurePolicy, error) {
+	if name == "" {
+		return PolicyDropNewest, nil
+	}
+	for _, p := range BackpressurePolicies() {
+		if string(p) == name {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown backpressure policy %q", name)
+}
+
+// ReceiverConfig holds receiver configuration
+type ReceiverConfig struct {
+	Port       int
+	Address    string
+	Endpoints  []Endpoint // Listened on instead of Address and Port if set
+	BufferSize int
+	Timeout    time.Duration
+
+	// Backpressure handling, see BackpressurePolicy
+	Policy     BackpressurePolicy // PolicyDropNewest if empty
+	QueueDepth int                // DefaultQueueDepth if zero
+	SpillDir   string             // Spill file directory, the system temporary directory if empty
+	SpillSize  int64              // Spill ring size in bytes, DefaultSpillSize if zero
+
+	// Forward re-sends every valid packet to these targets as it arrives
+	Forward []ForwardTarget
+}
+
+// ReceiverStats holds receiver statistics
+type ReceiverStats struct {
+	PacketsReceived uint64
+	BytesReceived   uint64
+	Errors          uint64 // Socket read errors
+	InvalidPackets  uint64 // Datagrams without a valid packet header
+	StartTime       time.Time
+
+	// Backpressure
+	QueueDepth    int    // Packets waiting in the channel
+	QueueCapacity int    // Size of the channel
+	SpillDepth    int    // Packets waiting in the spill ring
+	Spilled       uint64 // Packets that went through the spill ring
+	Blocked       uint64 // Packets that had to wait for room
+	DroppedNewest uint64 // Arriving packets dropped
+	DroppedOldest uint64 // Queued packets dropped to make room
+	DroppedSpill  uint64 // Spilled packets overwritten or lost to spill errors
+
+	// Forward holds the counters of each forward target
+	Forward []ForwardStats
+
+	// Sequence counts lost, duplicated and reordered packets and
+	// flashbacks per packet type, as seen on the wire, summed over senders
+	Sequence SequenceReport
+}
+
+// NewReceiver creates a new telemetry receiver
+func NewReceiver(config ReceiverConfig) *Receiver {
+	if config.QueueDepth <= 0 {
+		config.QueueDepth = DefaultQueueDepth
+	}
+	return &Receiver{
+		config:   config,
+		packets:  make(chan *RecordedPacket, config.QueueDepth),
+		pool:     newPacketPool(config.BufferSize),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins receiving telemetry packets. A stopped receiver can be
+// started again; packets then arrive on a new Packets channel.
+func (r *Receiver) Start() error {
+	r.control.Lock()
+	defer r.control.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return fmt.Errorf("receiver already running")
+	}
+
+	policy, err := ParseBackpressurePolicy(string(r.config.Policy))
+	if err != nil {
+		return err
+	}
+	r.config.Policy = policy
+
+	endpoints := r.endpoints()
+	conns := make([]*net.UDPConn, 0, len(endpoints))
+	closeAll := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	for _, endpoint := range endpoints {
+		conn, err := endpoint.listen()
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to bind UDP socket %s: %w", endpoint, err)
+		}
+		conns = append(conns, conn)
+	}
+
+	if len(r.config.Forward) > 0 {
+		forward, err := NewForwarder(r.config.Forward)
+		if err != nil {
+			closeAll()
+			return err
+		}
+		r.forward = forward
+	}
+
+	if policy == PolicySpill {
+		spill, err := newSpillRing(r.config.SpillDir, r.config.SpillSize)
+		if err != nil {
+			closeAll()
+			if r.forward != nil {
+				r.forward.Close()
+			}
+			return err
+		}
+		r.spill = spill
+	}
+
+	if r.stopped {
+		r.packets = make(chan *RecordedPacket, r.config.QueueDepth)
+		r.stopChan = make(chan struct{})
+		r.stopped = false
+	}
+	r.conns = conns
+	r.running = true
+	r.stats = ReceiverStats{StartTime: time.Now()}
+	r.sequence = make(map[Origin]*SequenceTracker)
+
+	for i, conn := range conns {
+		r.wg.Add(1)
+		go r.receiveLoop(conn, endpoints[i].String())
+	}
+	if r.spill != nil {
+		r.flush = make(chan struct{})
+		r.drained = make(chan struct{})
+		go r.drainSpill()
+	}
+
+	return nil
+}
+
+// Stop stops receiving telemetry packets and closes the Packets channel.
+// Packets still in the spill ring are handed on first, so the consumer
+// has to keep reading until the channel is closed.
+func (r *Receiver) Stop() error {
+	r.control.Lock()
+	defer r.control.Unlock()
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return nil
+	}
+
+	close(r.stopChan)
+	r.running = false
+
+	for _, conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+
+	// The loops take the lock to update stats, so wait without holding it
+	r.wg.Wait()
+	if r.spill != nil {
+		// Nothing is spilled any more, hand on what is left
+		close(r.flush)
+		<-r.drained
+		r.spill.Close()
+	}
+	close(r.packets)
+
+	if r.forward != nil {
+		r.forward.Close()
+	}
+
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Packets returns the channel for received packets. Consumers may call
+// Release on each packet when done with it to recycle its buffer.
+func (r *Receiver) Packets() <-chan *RecordedPacket {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.packets
+}
+
+// LocalAddr returns the address of the first endpoint, or nil if the
+// receiver has not been started
+func (r *Receiver) LocalAddr() net.Addr {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.conns) == 0 {
+		return nil
+	}
+	return r.conns[0].LocalAddr()
+}
+
+// LocalAddrs returns the addresses of all endpoints in configured order
+func (r *Receiver) LocalAddrs() []net.Addr {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	addrs := make([]net.Addr, len(r.conns))
+	for i, conn := range r.conns {
+		addrs[i] = conn.LocalAddr()
+	}
+	return addrs
+}
+
+// endpoints returns the configured endpoints
+func (r *Receiver) endpoints() []Endpoint {
+	if len(r.config.Endpoints) > 0 {
+		return r.config.Endpoints
+	}
+	return []Endpoint{{Address: r.config.Address, Port: r.config.Port}}
+}
+
+// Stats returns current receiver statistics
+func (r *Receiver) Stats() ReceiverStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := r.stats
+	for _, sequence := range r.sequence {
+		report := sequence.Report()
+		stats.Sequence.add(&report)
+	}
+	stats.QueueDepth = len(r.packets)
+	stats.QueueCapacity = cap(r.packets)
+	if r.spill != nil {
+		stats.SpillDepth = r.spill.Len()
+	}
+	if r.forward != nil {
+		stats.Forward = r.forward.Stats()
+	}
+	return stats
+}
+
+// IsRunning returns whether the receiver is active
+func (r *Receiver) IsRunning() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.running
+}
+
+// receiveLoop reads packets from one endpoint
+func (r *Receiver) receiveLoop(conn *net.UDPConn, endpoint string) {
+	defer r.wg.Done()
+
+	var packet *RecordedPacket
+
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		default:
+			// Set read deadline for responsive shutdown
+			conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+
+			if packet == nil {
+				packet = r.pool.get()
+			}
+			n, source, err := conn.ReadFromUDPAddrPort(packet.Data[:cap(packet.Data)])
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					continue // Normal timeout, keep trying
+				}
+				select {
+				case <-r.stopChan:
+					return // Connection closed during shutdown
+				default:
+				}
+				r.count(&r.stats.Errors)
+				continue
+			}
+
+			if n > 0 {
+				packet.Data = packet.Data[:n]
+				// Dual-stack sockets report IPv4 senders as mapped IPv6
+				source = netip.AddrPortFrom(source.Addr().Unmap(), source.Port())
+				packet.Origin = Origin{Endpoint: endpoint, Source: source}
+				if r.processPacket(packet) {
+					packet = nil
+				}
+			}
+		}
+	}
+}
+
+// processPacket stamps a received packet and queues it. It reports
+// whether the packet was handed on; if not, its buffer can be read into again.
+func (r *Receiver) processPacket(packet *RecordedPacket) bool {
+	// Parse header
+	if err := packet.Header.UnmarshalBinary(packet.Data); err != nil {
+		r.count(&r.stats.InvalidPackets)
+		return false
+	}
+	packet.Timestamp = time.Now()
+
+	// Forward before queueing so other apps see no queueing delay
+	if r.forward != nil {
+		r.forward.Forward(packet)
+	}
+
+	// Update stats
+	r.mu.Lock()
+	r.stats.PacketsReceived++
+	r.stats.BytesReceived += uint64(len(packet.Data))
+	sequence := r.sequence[packet.Origin]
+	if sequence == nil {
+		sequence = &SequenceTracker{}
+		r.sequence[packet.Origin] = sequence
+	}
+	sequence.Observe(&packet.Header)
+	r.mu.Unlock()
+
+	r.enqueue(packet)
+	return true
+}
+
+// enqueue hands a packet to the consumer, applying the backpressure
+// policy if the channel is full
+func (r *Receiver) enqueue(packet *RecordedPacket) {
+	// Keep order: once packets spill, later ones queue behind them
+	if r.spill == nil || r.spill.Len() == 0 {
+		select {
+		case r.packets <- packet:
+			return
+		default:
+		}
+	}
+
+	switch r.config.Policy {
+	case PolicyDropOldest:
+		for {
+			select {
+			case r.packets <- packet:
+				return
+			case old := <-r.packets:
+				r.count(&r.stats.DroppedOldest)
+				old.Release()
+			}
+		}
+	case PolicyBlock:
+		r.count(&r.stats.Blocked)
+		select {
+		case r.packets <- packet:
+		case <-r.stopChan:
+			packet.Release()
+		}
+	case PolicySpill:
+		overwritten, err := r.spill.push(packet)
+		r.mu.Lock()
+		r.stats.Spilled++
+		r.stats.DroppedSpill += uint64(overwritten)
+		if err != nil {
+			r.stats.DroppedSpill++
+		}
+		r.mu.Unlock()
+		packet.Release()
+	default:
+		r.count(&r.stats.DroppedNewest)
+		packet.Release()
+	}
+}
+
+// drainSpill moves spilled packets into the channel as room frees up,
+// and all that are left once Stop flushes the ring
+func (r *Receiver) drainSpill() {
+	defer close(r.drained)
+
+	for {
+		flushing := false
+		select {
+		case <-r.flush:
+			flushing = true
+		case <-r.spill.notify:
+		}
+
+		for {
+			packet := r.pool.get()
+			ok, err := r.spill.pop(packet)
+			if err != nil {
+				// The ring is unreadable, everything in it is lost
+				r.mu.Lock()
+				r.stats.DroppedSpill += uint64(r.spill.Len())
+				r.mu.Unlock()
+				r.spill.reset()
+			}
+			if !ok {
+				packet.Release()
+				break
+			}
+			packet.Header.UnmarshalBinary(packet.Data)
+
+			r.packets <- packet
+			r.spill.done()
+		}
+		if flushing {
+			return
+		}
+	}
+}
+
+// count increments one of the stats counters
+func (r *Receiver) count(counter *uint64) {
+	r.mu.Lock()
+	*counter++
+	r.mu.Unlock()
+}
+
+// packetPool recycles packets and their buffers between reads so the
+// receive path does not allocate once it has warmed up
+type packetPool struct {
+	pool sync.Pool
+}
+
+// newPacketPool returns a pool of packets with bufferSize byte buffers
+func newPacketPool(bufferSize int) *packetPool {
+	p := &packetPool{}
+	p.pool.New = func() any {
+		return &RecordedPacket{Data: make([]byte, bufferSize), pool: p}
+	}
+	return p
+}
+
+// get returns a packet to read into; its buffer is cap(pkt.Data) bytes.
+// Nothing of the packet's last use is kept.
+func (p *packetPool) get() *RecordedPacket {
+	pkt := p.pool.Get().(*RecordedPacket)
+	pkt.Header = PacketHeader{}
+	pkt.Origin = Origin{}
+	return pkt
+}
+
+// put returns a packet to the pool
+func (p *packetPool) put(pkt *RecordedPacket) {
+	p.pool.Put(pkt)
+}
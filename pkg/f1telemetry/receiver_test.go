@@ -0,0 +1,81 @@
+package f1telemetry
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// sendPackets sends n F1 25 motion packets to the receiver and waits
+// until it has read them
+func sendPackets(t *testing.T, r *Receiver, n int) {
+	t.Helper()
+	conn, err := net.DialUDP("udp", nil, r.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	start := r.Stats().PacketsReceived
+	data := make([]byte, PacketHeaderSize+8)
+	binary.LittleEndian.PutUint16(data, PacketFormat2025)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint32(data[23:], uint32(i))
+		if _, err := conn.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		// Stay within the socket buffer
+		for i > 16 && r.Stats().PacketsReceived < start+uint64(i-16) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for deadline := time.Now().Add(5 * time.Second); r.Stats().PacketsReceived < start+uint64(n); {
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d of %d packets", r.Stats().PacketsReceived-start, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReceiverStopDrainsSpill(t *testing.T) {
+	r := NewReceiver(ReceiverConfig{
+		Address:    "127.0.0.1",
+		BufferSize: 2048,
+		Policy:     PolicySpill,
+		QueueDepth: 4,
+		SpillDir:   t.TempDir(),
+	})
+	// The second run checks a stopped receiver starts again
+	for run := 0; run < 2; run++ {
+		if err := r.Start(); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		// Nothing reads until the receiver stops, so most packets spill
+		sendPackets(t, r, 200)
+		if stats := r.Stats(); stats.SpillDepth == 0 {
+			t.Fatalf("run %d: nothing spilled, %+v", run, stats)
+		}
+
+		packets := r.Packets()
+		stopped := make(chan error)
+		go func() { stopped <- r.Stop() }()
+
+		var frames []uint32
+		for packet := range packets {
+			frames = append(frames, packet.Header.OverallFrameIdentifier)
+			packet.Release()
+		}
+		if err := <-stopped; err != nil {
+			t.Fatal(err)
+		}
+		if len(frames) != 200 {
+			t.Fatalf("run %d: read %d of 200 packets", run, len(frames))
+		}
+		for i, frame := range frames {
+			if frame != uint32(i) {
+				t.Fatalf("run %d: packet %d is frame %d", run, i, frame)
+			}
+		}
+	}
+}
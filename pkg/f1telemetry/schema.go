@@ -0,0 +1,236 @@
+package f1telemetry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Packet layouts are declared by the packet structs themselves. Fields are
+// read in declaration order, packed little-endian, using the Go type of
+// each field as its wire type. Two struct tags refine this:
+//
+//	f1:"since=2024"  the field is only sent from that packet format on
+//	f1:"len=name"    only the first n array elements are sent, where n
+//	                 depends on the format (see formatLayout.arrayLen)
+//	unit:"km/h"      the unit of the value, reported by Schema
+//
+// Supported field types are the fixed-size integer and float types, arrays
+// of them and structs made of them.
+
+// Field describes one field of a packet layout
+type Field struct {
+	Name   string // Go path of the field, e.g. "CarTelemetryData[3].Speed"
+	Type   string // Wire type of each element, e.g. "uint16"
+	Unit   string // Empty if the value has no unit
+	Offset int    // Offset in bytes from the start of the packet
+	Size   int    // Size in bytes of all elements
+	Count  int    // Number of elements, 1 unless the field is an array
+}
+
+// PacketSchema describes the wire layout of a packet type in one format
+type PacketSchema struct {
+	Format uint16
+	ID     PacketType
+	Size   int
+	Fields []Field
+}
+
+// Schema returns the layout of a packet type in the given format, generated
+// from the same declarations the decoders use
+func Schema(format uint16, id PacketType) (*PacketSchema, error) {
+	if _, err := PacketSize(format, id); err != nil {
+		return nil, err
+	}
+	s := formatLayouts[format].schemas[id]
+	return &PacketSchema{
+		Format: format,
+		ID:     id,
+		Size:   s.size,
+		Fields: append([]Field(nil), s.fields...),
+	}, nil
+}
+
+// opKind is how a decodeOp moves bytes from the packet into the struct
+type opKind uint8
+
+const (
+	opCopy opKind = iota // copy n bytes as-is
+	opU16
+	opU32
+	opU64
+)
+
+// decodeOp moves one value from the packet into the destination struct
+type decodeOp struct {
+	kind opKind
+	n    int     // Bytes copied by opCopy
+	wire int     // Offset in the packet
+	mem  uintptr // Offset in the destination struct
+}
+
+// wireSchema is a struct layout compiled for one packet format
+type wireSchema struct {
+	typ    reflect.Type
+	size   int
+	ops    []decodeOp
+	fields []Field
+}
+
+// compileSchema builds the layout of struct type t for a packet format.
+// A nil layout compiles structs that are the same in every format. It
+// panics on declarations it cannot decode, so mistakes show up at start.
+func compileSchema(t reflect.Type, layout *formatLayout) *wireSchema {
+	s := &wireSchema{typ: t}
+	s.addStruct(t, "", 0, layout)
+	return s
+}
+
+// decode fills the struct at dst from data. The caller must check that
+// dst points to a value of s.typ and that data holds s.size bytes.
+func (s *wireSchema) decode(data []byte, dst unsafe.Pointer) {
+	for _, op := range s.ops {
+		p := unsafe.Add(dst, op.mem)
+		switch op.kind {
+		case opCopy:
+			copy(unsafe.Slice((*byte)(p), op.n), data[op.wire:op.wire+op.n])
+		case opU16:
+			*(*uint16)(p) = binary.LittleEndian.Uint16(data[op.wire:])
+		case opU32:
+			*(*uint32)(p) = binary.LittleEndian.Uint32(data[op.wire:])
+		case opU64:
+			*(*uint64)(p) = binary.LittleEndian.Uint64(data[op.wire:])
+		}
+	}
+}
+
+func (s *wireSchema) addStruct(t reflect.Type, prefix string, mem uintptr, layout *formatLayout) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := prefix + f.Name
+
+		count, ok := fieldCount(f, name, layout)
+		if !ok {
+			continue
+		}
+		s.addValue(f.Type, name, f.Tag.Get("unit"), mem+f.Offset, count, layout)
+	}
+}
+
+func (s *wireSchema) addValue(t reflect.Type, name, unit string, mem uintptr, count int, layout *formatLayout) {
+	switch t.Kind() {
+	case reflect.Struct:
+		s.addStruct(t, name+".", mem, layout)
+	case reflect.Array:
+		if count < 0 {
+			count = t.Len()
+		}
+		elem := t.Elem()
+		if !isScalar(elem.Kind()) {
+			for i := 0; i < count; i++ {
+				s.addValue(elem, fmt.Sprintf("%s[%d]", name, i), unit, mem+uintptr(i)*elem.Size(), -1, layout)
+			}
+			return
+		}
+		s.fields = append(s.fields, Field{
+			Name:   name,
+			Type:   elem.Kind().String(),
+			Unit:   unit,
+			Offset: s.size,
+			Size:   count * int(elem.Size()),
+			Count:  count,
+		})
+		for i := 0; i < count; i++ {
+			s.addOp(int(elem.Size()), mem+uintptr(i)*elem.Size())
+		}
+	default:
+		if !isScalar(t.Kind()) {
+			panic(fmt.Sprintf("f1telemetry: field %s of %s has unsupported type %s", name, s.typ, t))
+		}
+		s.fields = append(s.fields, Field{
+			Name:   name,
+			Type:   t.Kind().String(),
+			Unit:   unit,
+			Offset: s.size,
+			Size:   int(t.Size()),
+			Count:  1,
+		})
+		s.addOp(int(t.Size()), mem)
+	}
+}
+
+// addOp appends a read of n bytes at the current packet offset, merging
+// single bytes that follow each other in the packet and in memory
+func (s *wireSchema) addOp(n int, mem uintptr) {
+	op := decodeOp{wire: s.size, mem: mem, n: n}
+	s.size += n
+
+	switch n {
+	case 2:
+		op.kind = opU16
+	case 4:
+		op.kind = opU32
+	case 8:
+		op.kind = opU64
+	default:
+		op.kind = opCopy
+		if last := len(s.ops) - 1; last >= 0 {
+			prev := &s.ops[last]
+			if prev.kind == opCopy && prev.wire+prev.n == op.wire && prev.mem+uintptr(prev.n) == mem {
+				prev.n += n
+				return
+			}
+		}
+	}
+	s.ops = append(s.ops, op)
+}
+
+// fieldCount applies the f1 tag of a field. It reports whether the field
+// is sent in the layout's format and, for arrays, how many elements are
+// sent (-1 for all of them).
+func fieldCount(f reflect.StructField, name string, layout *formatLayout) (int, bool) {
+	tag, ok := f.Tag.Lookup("f1")
+	if !ok {
+		return -1, true
+	}
+	if layout == nil {
+		panic(fmt.Sprintf("f1telemetry: field %s has format-dependent tag %q", name, tag))
+	}
+
+	count := -1
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "since":
+			since, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				panic(fmt.Sprintf("f1telemetry: field %s has bad since %q", name, value))
+			}
+			if layout.format < uint16(since) {
+				return 0, false
+			}
+		case "len":
+			n, ok := layout.arrayLen(value)
+			if !ok || f.Type.Kind() != reflect.Array || n > f.Type.Len() {
+				panic(fmt.Sprintf("f1telemetry: field %s has bad len %q", name, value))
+			}
+			count = n
+		default:
+			panic(fmt.Sprintf("f1telemetry: field %s has unknown f1 tag option %q", name, opt))
+		}
+	}
+	return count, true
+}
+
+// isScalar reports whether k is a fixed-size wire type
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint8, reflect.Int8, reflect.Uint16, reflect.Int16,
+		reflect.Uint32, reflect.Int32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package f1telemetry
+
+import "math"
+
+// periodicPackets are the packet types the game sends at a steady rate, at
+// most once per frame. Gaps and duplicates are only detected for these;
+// the others are sent on demand or several to a frame.
+var periodicPackets = [numPacketTypes]bool{
+	PacketMotion:       true,
+	PacketSession:      true,
+	PacketLapData:      true,
+	PacketParticipants: true,
+	PacketCarSetups:    true,
+	PacketCarTelemetry: true,
+	PacketCarStatus:    true,
+	PacketCarDamage:    true,
+	PacketMotionEx:     true,
+	PacketTimeTrial:    true,
+}
+
+// gapFactor is how many times the usual frame step between two packets
+// must be exceeded before the packets in between count as lost
+const gapFactor = 1.5
+
+// SequenceStats counts irregularities in the stream of one packet type
+type SequenceStats struct {
+	Packets    uint64
+	Gaps       uint64 // Runs of lost packets
+	Missing    uint64 // Packets estimated lost in those gaps
+	Duplicates uint64 // Packets repeating the frame of the previous one
+	OutOfOrder uint64 // Packets older than one already seen
+	Rewinds    uint64 // Flashbacks: the frame or session time went back
+}
+
+// SequenceReport holds the sequence stats of every packet type, indexed by packet ID
+type SequenceReport [numPacketTypes]SequenceStats
+
+// Total sums the stats of all packet types. A flashback is seen by every
+// packet type, so Rewinds is the highest count of any type instead.
+func (r SequenceReport) Total() SequenceStats {
+	var total SequenceStats
+	for _, s := range r {
+		total.Packets += s.Packets
+		total.Gaps += s.Gaps
+		total.Missing += s.Missing
+		total.Duplicates += s.Duplicates
+		total.OutOfOrder += s.OutOfOrder
+		if s.Rewinds > total.Rewinds {
+			total.Rewinds = s.Rewinds
+		}
+	}
+	return total
+}
+
+// add adds the stats of another report to r
+func (r *SequenceReport) add(o *SequenceReport) {
+	for i := range r {
+		s, t := &r[i], &o[i]
+		s.Packets += t.Packets
+		s.Gaps += t.Gaps
+		s.Missing += t.Missing
+		s.Duplicates += t.Duplicates
+		s.OutOfOrder += t.OutOfOrder
+		s.Rewinds += t.Rewinds
+	}
+}
+
+// SequenceTracker detects lost, duplicated and reordered packets and
+// flashbacks from packet headers, separately for each packet type.
+// Ordering uses OverallFrameIdentifier, which never goes back; a
+// FrameIdentifier or SessionTime that goes back while it moves on is a
+// flashback. A packet that arrives late is also counted in the gap it left.
+type SequenceTracker struct {
+	streams [numPacketTypes]sequenceStream
+	report  SequenceReport
+}
+
+// sequenceStream is the last packet seen of one type
+type sequenceStream struct {
+	started     bool
+	sessionUID  uint64
+	overall     uint32
+	frame       uint32
+	sessionTime float32
+
+	// step is the smoothed number of frames between packets
+	step float64
+}
+
+// Observe records the header of a received packet
+func (t *SequenceTracker) Observe(h *PacketHeader) {
+	id := int(h.PacketID)
+	if id >= numPacketTypes {
+		return
+	}
+	s := &t.streams[id]
+	stats := &t.report[id]
+	stats.Packets++
+
+	if !s.started || h.SessionUID != s.sessionUID {
+		*s = sequenceStream{started: true, sessionUID: h.SessionUID}
+		s.remember(h)
+		return
+	}
+
+	switch {
+	case h.OverallFrameIdentifier < s.overall:
+		stats.OutOfOrder++
+		return
+	case h.OverallFrameIdentifier == s.overall:
+		if periodicPackets[id] {
+			stats.Duplicates++
+		}
+		return
+	}
+
+	if h.FrameIdentifier < s.frame || h.SessionTime < s.sessionTime {
+		stats.Rewinds++
+	}
+	if periodicPackets[id] {
+		s.checkGap(float64(h.OverallFrameIdentifier-s.overall), stats)
+	}
+	s.remember(h)
+}
+
+// Report returns the stats of every packet type
+func (t *SequenceTracker) Report() SequenceReport {
+	return t.report
+}
+
+// remember makes h the last packet seen
+func (s *sequenceStream) remember(h *PacketHeader) {
+	s.overall = h.OverallFrameIdentifier
+	s.frame = h.FrameIdentifier
+	s.sessionTime = h.SessionTime
+}
+
+// checkGap counts the packets missing in a step of delta frames and
+// otherwise folds delta into the usual step
+func (s *sequenceStream) checkGap(delta float64, stats *SequenceStats) {
+	if s.step == 0 {
+		s.step = delta
+		return
+	}
+	if delta < gapFactor*s.step {
+		s.step += (delta - s.step) / 8
+		return
+	}
+	stats.Gaps++
+	stats.Missing += uint64(math.Round(delta/s.step)) - 1
+}
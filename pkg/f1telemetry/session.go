@@ -0,0 +1,153 @@
+package f1telemetry
+
+// SessionPacketSize is the size of a Session packet (ID 1)
+const SessionPacketSize = 753
+
+const (
+	// MaxMarshalZones is the number of marshal zone slots in a Session packet
+	MaxMarshalZones = 21
+
+	// MaxWeatherForecastSamples is the number of forecast slots in a Session packet
+	MaxWeatherForecastSamples = 64
+
+	// MaxSessionsInWeekend is the number of weekend structure slots
+	MaxSessionsInWeekend = 12
+)
+
+// MarshalZone describes a marshal zone and the flag shown in it
+type MarshalZone struct {
+	ZoneStart float32 // Fraction (0..1) of the lap where the zone starts
+	ZoneFlag  int8    // -1 = unknown, 0 = none, 1 = green, 2 = blue, 3 = yellow
+}
+
+// WeatherForecastSample is one entry of the weather forecast
+type WeatherForecastSample struct {
+	SessionType            uint8
+	TimeOffset             uint8 `unit:"min"` // Time ahead the forecast is for
+	Weather                uint8 // 0 = clear ... 5 = storm
+	TrackTemperature       int8  `unit:"°C"`
+	TrackTemperatureChange int8  // 0 = up, 1 = down, 2 = no change
+	AirTemperature         int8  `unit:"°C"`
+	AirTemperatureChange   int8  // 0 = up, 1 = down, 2 = no change
+	RainPercentage         uint8 `unit:"%"`
+}
+
+// PacketSessionData is the decoded Session packet (ID 1)
+type PacketSessionData struct {
+	Header PacketHeader
+
+	Weather             uint8
+	TrackTemperature    int8 `unit:"°C"`
+	AirTemperature      int8 `unit:"°C"`
+	TotalLaps           uint8
+	TrackLength         uint16 `unit:"m"`
+	SessionType         uint8
+	TrackID             int8
+	Formula             uint8
+	SessionTimeLeft     uint16 `unit:"s"`
+	SessionDuration     uint16 `unit:"s"`
+	PitSpeedLimit       uint8  `unit:"km/h"`
+	GamePaused          uint8
+	IsSpectating        uint8
+	SpectatorCarIndex   uint8
+	SliProNativeSupport uint8
+	NumMarshalZones     uint8
+	MarshalZones        [MaxMarshalZones]MarshalZone
+	SafetyCarStatus     uint8 // 0 = none, 1 = full, 2 = virtual, 3 = formation lap
+	NetworkGame         uint8
+
+	NumWeatherForecastSamples uint8
+	WeatherForecastSamples    [MaxWeatherForecastSamples]WeatherForecastSample `f1:"len=forecast"` // 56 sent in F1 23
+	ForecastAccuracy          uint8
+	AIDifficulty              uint8
+
+	SeasonLinkIdentifier  uint32
+	WeekendLinkIdentifier uint32
+	SessionLinkIdentifier uint32
+
+	PitStopWindowIdealLap  uint8
+	PitStopWindowLatestLap uint8
+	PitStopRejoinPosition  uint8
+
+	SteeringAssist        uint8
+	BrakingAssist         uint8
+	GearboxAssist         uint8
+	PitAssist             uint8
+	PitReleaseAssist      uint8
+	ERSAssist             uint8
+	DRSAssist             uint8
+	DynamicRacingLine     uint8
+	DynamicRacingLineType uint8
+	GameMode              uint8
+	RuleSet               uint8
+
+	TimeOfDay uint32 `unit:"min"` // Time since midnight
+
+	SessionLength                   uint8
+	SpeedUnitsLeadPlayer            uint8
+	TemperatureUnitsLeadPlayer      uint8
+	SpeedUnitsSecondaryPlayer       uint8
+	TemperatureUnitsSecondaryPlayer uint8
+	NumSafetyCarPeriods             uint8
+	NumVirtualSafetyCarPeriods      uint8
+	NumRedFlagPeriods               uint8
+
+	// Added in F1 24
+	EqualCarPerformance          uint8 `f1:"since=2024"`
+	RecoveryMode                 uint8 `f1:"since=2024"`
+	FlashbackLimit               uint8 `f1:"since=2024"`
+	SurfaceType                  uint8 `f1:"since=2024"`
+	LowFuelMode                  uint8 `f1:"since=2024"`
+	RaceStarts                   uint8 `f1:"since=2024"`
+	TyreTemperature              uint8 `f1:"since=2024"`
+	PitLaneTyreSim               uint8 `f1:"since=2024"`
+	CarDamage                    uint8 `f1:"since=2024"`
+	CarDamageRate                uint8 `f1:"since=2024"`
+	Collisions                   uint8 `f1:"since=2024"`
+	CollisionsOffForFirstLapOnly uint8 `f1:"since=2024"`
+	MPUnsafePitRelease           uint8 `f1:"since=2024"`
+	MPOffForGriefing             uint8 `f1:"since=2024"`
+	CornerCuttingStringency      uint8 `f1:"since=2024"`
+	ParcFermeRules               uint8 `f1:"since=2024"`
+	PitStopExperience            uint8 `f1:"since=2024"`
+	SafetyCar                    uint8 `f1:"since=2024"`
+	SafetyCarExperience          uint8 `f1:"since=2024"`
+	FormationLap                 uint8 `f1:"since=2024"`
+	FormationLapExperience       uint8 `f1:"since=2024"`
+	RedFlags                     uint8 `f1:"since=2024"`
+	AffectsLicenceLevelSolo      uint8 `f1:"since=2024"`
+	AffectsLicenceLevelMP        uint8 `f1:"since=2024"`
+
+	NumSessionsInWeekend    uint8                       `f1:"since=2024"`
+	WeekendStructure        [MaxSessionsInWeekend]uint8 `f1:"since=2024"`
+	Sector2LapDistanceStart float32                     `f1:"since=2024" unit:"m"`
+	Sector3LapDistanceStart float32                     `f1:"since=2024" unit:"m"`
+}
+
+// ActiveMarshalZones returns the marshal zones that are in use
+func (s *PacketSessionData) ActiveMarshalZones() []MarshalZone {
+	n := int(s.NumMarshalZones)
+	if n > MaxMarshalZones {
+		n = MaxMarshalZones
+	}
+	return s.MarshalZones[:n]
+}
+
+// Forecast returns the weather forecast samples that are in use
+func (s *PacketSessionData) Forecast() []WeatherForecastSample {
+	n := int(s.NumWeatherForecastSamples)
+	if n > MaxWeatherForecastSamples {
+		n = MaxWeatherForecastSamples
+	}
+	return s.WeatherForecastSamples[:n]
+}
+
+// ParseSessionPacket decodes the full Session packet (ID 1)
+func ParseSessionPacket(data []byte) (*PacketSessionData, error) {
+	return parsePacket[PacketSessionData](data, PacketSession)
+}
+
+// UnmarshalBinary decodes a Session packet (ID 1) into s, reusing its storage
+func (s *PacketSessionData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketSession, s)
+}
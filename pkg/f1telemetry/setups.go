@@ -0,0 +1,54 @@
+package f1telemetry
+
+// CarSetupsPacketSize is the size of a Car Setups packet (ID 5)
+const CarSetupsPacketSize = 1133
+
+// CarSetupData holds the setup of a single car
+type CarSetupData struct {
+	FrontWing              uint8
+	RearWing               uint8
+	OnThrottle             uint8   `unit:"%"` // Differential on throttle
+	OffThrottle            uint8   `unit:"%"` // Differential off throttle
+	FrontCamber            float32 `unit:"°"`
+	RearCamber             float32 `unit:"°"`
+	FrontToe               float32 `unit:"°"`
+	RearToe                float32 `unit:"°"`
+	FrontSuspension        uint8
+	RearSuspension         uint8
+	FrontAntiRollBar       uint8
+	RearAntiRollBar        uint8
+	FrontSuspensionHeight  uint8
+	RearSuspensionHeight   uint8
+	BrakePressure          uint8   `unit:"%"`
+	BrakeBias              uint8   `unit:"%"`
+	EngineBraking          uint8   `f1:"since=2024" unit:"%"`
+	RearLeftTyrePressure   float32 `unit:"psi"`
+	RearRightTyrePressure  float32 `unit:"psi"`
+	FrontLeftTyrePressure  float32 `unit:"psi"`
+	FrontRightTyrePressure float32 `unit:"psi"`
+	Ballast                uint8
+	FuelLoad               float32 `unit:"kg"`
+}
+
+// PacketCarSetupData is the decoded Car Setups packet (ID 5)
+type PacketCarSetupData struct {
+	Header             PacketHeader
+	CarSetups          [MaxCars]CarSetupData
+	NextFrontWingValue float32 `f1:"since=2024"` // Front wing after next pit stop, player only
+}
+
+// IsBlank reports whether the setup is empty, as sent for cars whose
+// setup is hidden in multiplayer or while spectating
+func (c *CarSetupData) IsBlank() bool {
+	return *c == CarSetupData{}
+}
+
+// ParseCarSetupsPacket decodes setups for all cars from packet ID 5
+func ParseCarSetupsPacket(data []byte) (*PacketCarSetupData, error) {
+	return parsePacket[PacketCarSetupData](data, PacketCarSetups)
+}
+
+// UnmarshalBinary decodes a Car Setups packet (ID 5) into p, reusing its storage
+func (p *PacketCarSetupData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketCarSetups, p)
+}
@@ -0,0 +1,228 @@
+package f1telemetry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultSpillSize is the size of the spill ring when none is configured
+const DefaultSpillSize = 64 << 20
+
+// spillRecordHeader is the size of the timestamp, length and origin ID
+// stored before each spilled packet
+const spillRecordHeader = 16
+
+// spillRing is a fixed-size ring of packets kept in a temporary file. The
+// receive loop pushes packets the channel has no room for and a drain
+// goroutine pops them in order once it has. When the ring is full the
+// oldest packets are overwritten.
+type spillRing struct {
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	head    int64 // Offset of the oldest record
+	used    int64 // Bytes in use from head on
+	records int   // Records in the ring
+	popping bool  // A popped record has not been handed on yet
+
+	notify chan struct{} // Signalled when a record is pushed
+	header [spillRecordHeader]byte
+
+	// Origins of spilled packets by the ID stored with them
+	origins   []Origin
+	originIDs map[Origin]uint32
+}
+
+// newSpillRing creates a spill ring of size bytes in dir, the system
+// temporary directory if empty. The file is removed once it is open so
+// nothing is left behind.
+func newSpillRing(dir string, size int64) (*spillRing, error) {
+	if size <= 0 {
+		size = DefaultSpillSize
+	}
+	file, err := os.CreateTemp(dir, "f1telemetry-spill-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	os.Remove(file.Name())
+
+	return &spillRing{
+		file:      file,
+		size:      size,
+		notify:    make(chan struct{}, 1),
+		originIDs: make(map[Origin]uint32),
+	}, nil
+}
+
+// Len returns the number of packets waiting in the ring, including one
+// that has been popped but not handed on yet
+func (s *spillRing) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.records
+	if s.popping {
+		n++
+	}
+	return n
+}
+
+// push appends a packet to the ring. It returns the number of older
+// packets that were overwritten to make room.
+func (s *spillRing) push(packet *RecordedPacket) (int, error) {
+	n := int64(spillRecordHeader + len(packet.Data))
+	if n > s.size {
+		return 0, fmt.Errorf("packet of %d bytes does not fit spill ring", len(packet.Data))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := 0
+	for s.used+n > s.size {
+		if err := s.skip(); err != nil {
+			return dropped, err
+		}
+		dropped++
+	}
+
+	binary.LittleEndian.PutUint64(s.header[0:], uint64(packet.Timestamp.UnixNano()))
+	binary.LittleEndian.PutUint32(s.header[8:], uint32(len(packet.Data)))
+	binary.LittleEndian.PutUint32(s.header[12:], s.originID(packet.Origin))
+	tail := (s.head + s.used) % s.size
+	if err := s.writeAt(s.header[:], tail); err != nil {
+		return dropped, err
+	}
+	if err := s.writeAt(packet.Data, (tail+spillRecordHeader)%s.size); err != nil {
+		return dropped, err
+	}
+	s.used += n
+	s.records++
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return dropped, nil
+}
+
+// pop reads the oldest packet into packet. It reports false if the ring
+// is empty. The caller must call done once the packet has been handed on.
+func (s *spillRing) pop(packet *RecordedPacket) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.records == 0 {
+		return false, nil
+	}
+	length, timestamp, origin, err := s.readHeader()
+	if err != nil {
+		return false, err
+	}
+	if length > cap(packet.Data) {
+		return false, fmt.Errorf("spilled packet of %d bytes exceeds buffer", length)
+	}
+	packet.Data = packet.Data[:length]
+	if err := s.readAt(packet.Data, (s.head+spillRecordHeader)%s.size); err != nil {
+		return false, err
+	}
+	packet.Timestamp = timestamp
+	packet.Origin = s.origins[origin]
+
+	s.advance(int64(spillRecordHeader + length))
+	s.popping = true
+	return true, nil
+}
+
+// done marks the last popped packet as handed on
+func (s *spillRing) done() {
+	s.mu.Lock()
+	s.popping = false
+	s.mu.Unlock()
+}
+
+// reset empties the ring
+func (s *spillRing) reset() {
+	s.mu.Lock()
+	s.head, s.used, s.records, s.popping = 0, 0, 0, false
+	s.mu.Unlock()
+}
+
+// Close removes the ring
+func (s *spillRing) Close() error {
+	return s.file.Close()
+}
+
+// skip drops the oldest record
+func (s *spillRing) skip() error {
+	length, _, _, err := s.readHeader()
+	if err != nil {
+		return err
+	}
+	s.advance(int64(spillRecordHeader + length))
+	return nil
+}
+
+// advance moves head past a record of n bytes
+func (s *spillRing) advance(n int64) {
+	s.head = (s.head + n) % s.size
+	s.used -= n
+	s.records--
+}
+
+// readHeader reads the length, timestamp and origin ID of the oldest record
+func (s *spillRing) readHeader() (int, time.Time, uint32, error) {
+	if err := s.readAt(s.header[:], s.head); err != nil {
+		return 0, time.Time{}, 0, err
+	}
+	timestamp := time.Unix(0, int64(binary.LittleEndian.Uint64(s.header[0:])))
+	length := int(binary.LittleEndian.Uint32(s.header[8:]))
+	origin := binary.LittleEndian.Uint32(s.header[12:])
+	if int(origin) >= len(s.origins) {
+		return 0, time.Time{}, 0, fmt.Errorf("spilled packet has unknown origin %d", origin)
+	}
+	return length, timestamp, origin, nil
+}
+
+// originID returns the ID spilled packets from origin are stored with.
+// IDs are kept for the life of the ring; there are only a few senders.
+func (s *spillRing) originID(origin Origin) uint32 {
+	id, ok := s.originIDs[origin]
+	if !ok {
+		id = uint32(len(s.origins))
+		s.origins = append(s.origins, origin)
+		s.originIDs[origin] = id
+	}
+	return id
+}
+
+// writeAt writes p at off, wrapping around the end of the ring
+func (s *spillRing) writeAt(p []byte, off int64) error {
+	first := min(int64(len(p)), s.size-off)
+	if _, err := s.file.WriteAt(p[:first], off); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if first < int64(len(p)) {
+		if _, err := s.file.WriteAt(p[first:], 0); err != nil {
+			return fmt.Errorf("failed to write spill file: %w", err)
+		}
+	}
+	return nil
+}
+
+// readAt reads len(p) bytes at off, wrapping around the end of the ring
+func (s *spillRing) readAt(p []byte, off int64) error {
+	first := min(int64(len(p)), s.size-off)
+	if _, err := s.file.ReadAt(p[:first], off); err != nil {
+		return fmt.Errorf("failed to read spill file: %w", err)
+	}
+	if first < int64(len(p)) {
+		if _, err := s.file.ReadAt(p[first:], 0); err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package f1telemetry
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSpillRingKeepsOrigins(t *testing.T) {
+	ring, err := newSpillRing(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ring.Close()
+
+	origins := []Origin{
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.20:50123")},
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.21:50124")},
+		{Endpoint: "[::]:20778", Source: netip.MustParseAddrPort("[fe80::1]:50125")},
+	}
+
+	// Enough packets to wrap the ring and overwrite the oldest
+	var pushed []RecordedPacket
+	dropped := 0
+	for i := 0; i < 40; i++ {
+		packet := RecordedPacket{
+			Timestamp: time.Unix(1700000000, int64(i)),
+			Data:      bytes.Repeat([]byte{byte(i)}, 20+i%7),
+			Origin:    origins[i%len(origins)],
+		}
+		n, err := ring.push(&packet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dropped += n
+		pushed = append(pushed, packet)
+	}
+	if dropped == 0 {
+		t.Fatal("ring did not wrap")
+	}
+
+	pool := newPacketPool(64)
+	for _, want := range pushed[dropped:] {
+		// Pool packets carry the origin of their last use
+		packet := pool.get()
+		packet.Origin = origins[0]
+		packet.Header.PacketID = 99
+
+		ok, err := ring.pop(packet)
+		if err != nil || !ok {
+			t.Fatalf("pop = %v, %v", ok, err)
+		}
+		ring.done()
+		if !bytes.Equal(packet.Data, want.Data) || !packet.Timestamp.Equal(want.Timestamp) || packet.Origin != want.Origin {
+			t.Fatalf("popped %v from %v, want %v from %v", packet.Data, packet.Origin, want.Data, want.Origin)
+		}
+		packet.Release()
+	}
+	if ok, _ := ring.pop(pool.get()); ok {
+		t.Error("ring not empty")
+	}
+
+	packet := pool.get()
+	if packet.Origin != (Origin{}) || packet.Header != (PacketHeader{}) {
+		t.Error("pool packet keeps its last origin or header")
+	}
+}
@@ -0,0 +1,331 @@
+package f1telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultFramePackets are the packet types the game sends once per frame
+// at the rate set in its telemetry settings. A frame is complete once all
+// of them have arrived.
+var DefaultFramePackets = []PacketType{
+	PacketMotion,
+	PacketLapData,
+	PacketCarTelemetry,
+	PacketCarStatus,
+}
+
+// lateFrames is how far behind the current frame a packet may be and still
+// count as arriving late. Packets further back mean the stream went back,
+// such as a recording being rewound, and the tracker starts over.
+const lateFrames = 60
+
+// CarState is everything decoded for one car. Parts that are sent less
+// often than every frame keep their last received value.
+type CarState struct {
+	Timestamp   time.Time // Arrival of the last packet that updated the car
+	Participant ParticipantData
+	Motion      CarMotionData
+	Lap         LapData
+	Telemetry   CarTelemetryData
+	Status      CarStatusData
+	Damage      CarDamageData
+	Setup       CarSetupData
+}
+
+// FrameState is a snapshot of the session and all cars at one frame
+type FrameState struct {
+	SessionUID             uint64
+	FrameIdentifier        uint32 // Goes back after a flashback
+	OverallFrameIdentifier uint32 // Never goes back
+	SessionTime            float32
+	Timestamp              time.Time // Arrival of the last packet of the frame
+	PlayerCarIndex         uint8
+
+	// Received has bit n set if a packet with ID n arrived in this frame
+	Received uint32
+
+	// Complete is set once all packets the tracker waits for arrived
+	Complete bool
+
+	Session  PacketSessionData
+	MotionEx PacketMotionExData // Player car only
+	Cars     [MaxCars]CarState
+}
+
+// HasPacket reports whether a packet of the given type arrived in the frame
+func (f *FrameState) HasPacket(id PacketType) bool {
+	return f.Received&(1<<id) != 0
+}
+
+// PlayerCar returns the state of the player car, or nil when spectating
+func (f *FrameState) PlayerCar() *CarState {
+	if int(f.PlayerCarIndex) >= MaxCars {
+		return nil
+	}
+	return &f.Cars[f.PlayerCarIndex]
+}
+
+// TelemetryData returns the values shown in the live display
+func (c *CarState) TelemetryData() *TelemetryData {
+	t := &TelemetryData{}
+	c.Lap.updateTelemetryData(t)
+	c.Status.updateTelemetryData(t)
+	c.Telemetry.updateTelemetryData(t)
+	return t
+}
+
+// StateTracker merges decoded packets into per-car state frame by frame.
+// Packets are grouped by OverallFrameIdentifier; a frame is published once
+// every required packet type for it has arrived, so readers never see a
+// frame half merged. It is safe for concurrent use.
+type StateTracker struct {
+	mu       sync.Mutex
+	required uint32
+	started  bool
+	current  FrameState
+	latest   FrameState
+	complete bool // latest holds a complete frame
+
+	// Decode buffers reused between packets
+	motion       PacketMotionData
+	lapData      LapDataPacket
+	participants PacketParticipantsData
+	carSetups    PacketCarSetupData
+	carTelemetry PacketCarTelemetryData
+	carStatus    PacketCarStatusData
+	carDamage    PacketCarDamageData
+}
+
+// NewStateTracker creates a tracker that treats a frame as complete once
+// the given packet types arrived for it, DefaultFramePackets if none
+func NewStateTracker(required ...PacketType) *StateTracker {
+	if len(required) == 0 {
+		required = DefaultFramePackets
+	}
+	t := &StateTracker{}
+	for _, id := range required {
+		t.required |= 1 << id
+	}
+	return t
+}
+
+// Update merges a packet into the current frame. It reports whether the
+// packet completed the frame, in which case Latest returns it. Packets
+// from the few frames before the current one are late and ignored.
+func (t *StateTracker) Update(packet *RecordedPacket) (bool, error) {
+	h := &packet.Header
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.started && h.SessionUID != t.current.SessionUID {
+		// New session, forget everything from the old one
+		t.reset()
+	}
+	if t.started && h.OverallFrameIdentifier < t.current.OverallFrameIdentifier {
+		if t.current.OverallFrameIdentifier-h.OverallFrameIdentifier <= lateFrames {
+			return false, nil
+		}
+		t.reset()
+	}
+	if !t.started || h.OverallFrameIdentifier > t.current.OverallFrameIdentifier {
+		t.startFrame(h)
+	}
+
+	if err := t.apply(packet); err != nil {
+		return false, err
+	}
+	t.current.Received |= 1 << h.PacketID
+	t.current.Timestamp = packet.Timestamp
+
+	if t.current.Complete || t.current.Received&t.required != t.required {
+		return false, nil
+	}
+	t.current.Complete = true
+	t.latest = t.current
+	t.complete = true
+	return true, nil
+}
+
+// HandlePacket makes the tracker a pipeline Stage
+func (t *StateTracker) HandlePacket(ctx context.Context, packet *RecordedPacket) error {
+	_, err := t.Update(packet)
+	return err
+}
+
+// Reset forgets all frames, such as after seeking in a recording
+func (t *StateTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reset()
+}
+
+// reset forgets all frames; t.mu must be held
+func (t *StateTracker) reset() {
+	t.current = FrameState{}
+	t.complete = false
+	t.started = false
+}
+
+// Latest copies the last complete frame into dst. It reports false if no
+// frame has completed yet.
+func (t *StateTracker) Latest(dst *FrameState) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.complete {
+		return false
+	}
+	*dst = t.latest
+	return true
+}
+
+// OriginTrackers keeps a StateTracker per packet origin, so packets from
+// several games sending to one receiver are not merged into one state. It
+// is safe for concurrent use.
+type OriginTrackers struct {
+	mu       sync.Mutex
+	required []PacketType
+	trackers map[Origin]*StateTracker
+	first    *StateTracker
+}
+
+// NewOriginTrackers creates per-origin trackers that treat a frame as
+// complete once the given packet types arrived for it, DefaultFramePackets
+// if none
+func NewOriginTrackers(required ...PacketType) *OriginTrackers {
+	return &OriginTrackers{
+		required: required,
+		trackers: make(map[Origin]*StateTracker),
+	}
+}
+
+// Update merges a packet into the tracker of its origin, see
+// StateTracker.Update
+func (o *OriginTrackers) Update(packet *RecordedPacket) (bool, error) {
+	o.mu.Lock()
+	t := o.trackers[packet.Origin]
+	if t == nil {
+		t = NewStateTracker(o.required...)
+		o.trackers[packet.Origin] = t
+		if o.first == nil {
+			o.first = t
+		}
+	}
+	o.mu.Unlock()
+	return t.Update(packet)
+}
+
+// HandlePacket makes the trackers a pipeline Stage
+func (o *OriginTrackers) HandlePacket(ctx context.Context, packet *RecordedPacket) error {
+	_, err := o.Update(packet)
+	return err
+}
+
+// Tracker returns the tracker of an origin, nil if no packet came from it
+func (o *OriginTrackers) Tracker(origin Origin) *StateTracker {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.trackers[origin]
+}
+
+// First returns the tracker of the origin of the first packet, nil if
+// there was none yet
+func (o *OriginTrackers) First() *StateTracker {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.first
+}
+
+// Reset forgets all frames of every origin
+func (o *OriginTrackers) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, t := range o.trackers {
+		t.Reset()
+	}
+}
+
+// startFrame begins a new frame, carrying the car state over
+func (t *StateTracker) startFrame(h *PacketHeader) {
+	t.started = true
+	t.current.SessionUID = h.SessionUID
+	t.current.FrameIdentifier = h.FrameIdentifier
+	t.current.OverallFrameIdentifier = h.OverallFrameIdentifier
+	t.current.SessionTime = h.SessionTime
+	t.current.PlayerCarIndex = h.PlayerCarIndex
+	t.current.Received = 0
+	t.current.Complete = false
+}
+
+// apply decodes a packet into the current frame
+func (t *StateTracker) apply(packet *RecordedPacket) error {
+	f := &t.current
+	cars := &f.Cars
+
+	switch PacketType(packet.Header.PacketID) {
+	case PacketMotion:
+		if err := t.motion.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Motion = t.motion.CarMotionData[i]
+		}
+	case PacketSession:
+		return f.Session.UnmarshalBinary(packet.Data)
+	case PacketLapData:
+		if err := t.lapData.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Lap = t.lapData.LapData[i]
+		}
+	case PacketParticipants:
+		if err := t.participants.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Participant = t.participants.Participants[i]
+		}
+	case PacketCarSetups:
+		if err := t.carSetups.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Setup = t.carSetups.CarSetups[i]
+		}
+	case PacketCarTelemetry:
+		if err := t.carTelemetry.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Telemetry = t.carTelemetry.CarTelemetryData[i]
+		}
+	case PacketCarStatus:
+		if err := t.carStatus.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Status = t.carStatus.CarStatusData[i]
+		}
+	case PacketCarDamage:
+		if err := t.carDamage.UnmarshalBinary(packet.Data); err != nil {
+			return err
+		}
+		for i := range cars {
+			cars[i].Damage = t.carDamage.CarDamageData[i]
+		}
+	case PacketMotionEx:
+		return f.MotionEx.UnmarshalBinary(packet.Data)
+	default:
+		// Events, results and histories are not part of the car state
+		return nil
+	}
+
+	for i := range cars {
+		cars[i].Timestamp = packet.Timestamp
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package f1telemetry
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// newFramePacket returns a zeroed F1 25 packet of the given type and frame
+func newFramePacket(t *testing.T, id PacketType, session uint64, frame uint32) *RecordedPacket {
+	t.Helper()
+	size, err := PacketSize(PacketFormat2025, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, size)
+	binary.LittleEndian.PutUint16(data, PacketFormat2025)
+	data[6] = uint8(id)
+	binary.LittleEndian.PutUint64(data[7:], session)
+	binary.LittleEndian.PutUint32(data[19:], frame)
+	binary.LittleEndian.PutUint32(data[23:], frame)
+	packet := &RecordedPacket{Timestamp: time.Unix(1700000000, int64(frame)), Data: data}
+	if err := packet.Header.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	return packet
+}
+
+// updateFrame feeds the tracker every packet of a frame and fails unless
+// Latest returns it afterwards
+func updateFrame(t *testing.T, tracker *StateTracker, session uint64, frame uint32) {
+	t.Helper()
+	for i, id := range DefaultFramePackets {
+		complete, err := tracker.Update(newFramePacket(t, id, session, frame))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if complete != (i == len(DefaultFramePackets)-1) {
+			t.Fatalf("frame %d complete after %d packets", frame, i+1)
+		}
+	}
+	var latest FrameState
+	if !tracker.Latest(&latest) || latest.OverallFrameIdentifier != frame || latest.SessionUID != session {
+		t.Fatalf("latest is frame %d of %x, want %d of %x", latest.OverallFrameIdentifier, latest.SessionUID, frame, session)
+	}
+}
+
+func TestStateTrackerFrames(t *testing.T) {
+	tracker := NewStateTracker()
+	updateFrame(t, tracker, 1, 1000)
+	updateFrame(t, tracker, 1, 1001)
+
+	// Late packets of an earlier frame are ignored
+	if complete, err := tracker.Update(newFramePacket(t, PacketMotion, 1, 999)); complete || err != nil {
+		t.Fatalf("late packet returned %v, %v", complete, err)
+	}
+	updateFrame(t, tracker, 1, 1002)
+
+	// Going back further starts over, as after rewinding a recording
+	updateFrame(t, tracker, 1, 100)
+
+	// And so does a new session
+	updateFrame(t, tracker, 2, 50)
+}
+
+func TestStateTrackerReset(t *testing.T) {
+	tracker := NewStateTracker()
+	updateFrame(t, tracker, 1, 1000)
+
+	tracker.Reset()
+	var latest FrameState
+	if tracker.Latest(&latest) {
+		t.Fatal("Latest returned a frame after Reset")
+	}
+	updateFrame(t, tracker, 1, 999)
+}
+
+func TestOriginTrackers(t *testing.T) {
+	trackers := NewOriginTrackers()
+	if trackers.First() != nil {
+		t.Fatal("First returned a tracker before any packet")
+	}
+	origins := []Origin{
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.20:50123")},
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.21:50124")},
+	}
+
+	// Two games in different sessions and frames, interleaved
+	frames := []uint32{1000, 20}
+	for _, id := range DefaultFramePackets {
+		for i, origin := range origins {
+			packet := newFramePacket(t, id, uint64(i+1), frames[i])
+			packet.Origin = origin
+			if _, err := trackers.Update(packet); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for i, origin := range origins {
+		var latest FrameState
+		if !trackers.Tracker(origin).Latest(&latest) || latest.SessionUID != uint64(i+1) || latest.OverallFrameIdentifier != frames[i] {
+			t.Errorf("%v: latest is frame %d of %x", origin, latest.OverallFrameIdentifier, latest.SessionUID)
+		}
+	}
+	if trackers.First() != trackers.Tracker(origins[0]) {
+		t.Error("First is not the tracker of the first origin")
+	}
+
+	trackers.Reset()
+	var latest FrameState
+	if trackers.First().Latest(&latest) {
+		t.Error("Latest returned a frame after Reset")
+	}
+}
+
+func TestCarStateTelemetryData(t *testing.T) {
+	var car CarState
+	car.Telemetry.DRS = 0
+	car.Status.DRSAllowed = 1
+	if td := car.TelemetryData(); td.DRS != 0 || td.DRSAllowed != 1 {
+		t.Errorf("DRS %d, allowed %d, want 0 and 1", td.DRS, td.DRSAllowed)
+	}
+
+	car.Telemetry.DRS = 1
+	car.Status.DRSAllowed = 0
+	if td := car.TelemetryData(); td.DRS != 1 || td.DRSAllowed != 0 {
+		t.Errorf("DRS %d, allowed %d, want 1 and 0", td.DRS, td.DRSAllowed)
+	}
+}
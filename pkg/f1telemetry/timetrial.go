@@ -0,0 +1,45 @@
+package f1telemetry
+
+import "time"
+
+// TimeTrialPacketSize is the size of a Time Trial packet (ID 14)
+const TimeTrialPacketSize = 101
+
+// TimeTrialDataSet holds one time trial lap and the assists used for it
+type TimeTrialDataSet struct {
+	CarIdx              uint8
+	TeamID              uint8
+	LapTimeInMS         uint32 `unit:"ms"`
+	Sector1TimeInMS     uint32 `unit:"ms"`
+	Sector2TimeInMS     uint32 `unit:"ms"`
+	Sector3TimeInMS     uint32 `unit:"ms"`
+	TractionControl     uint8  // 0 = assist off, 1 = assist on
+	GearboxAssist       uint8
+	AntiLockBrakes      uint8
+	EqualCarPerformance uint8 // 0 = realistic, 1 = equal
+	CustomSetup         uint8
+	Valid               uint8
+}
+
+// PacketTimeTrialData is the decoded Time Trial packet (ID 14)
+type PacketTimeTrialData struct {
+	Header                   PacketHeader
+	PlayerSessionBestDataSet TimeTrialDataSet
+	PersonalBestDataSet      TimeTrialDataSet
+	RivalDataSet             TimeTrialDataSet
+}
+
+// LapTime returns the lap time as a duration
+func (t *TimeTrialDataSet) LapTime() time.Duration {
+	return time.Duration(t.LapTimeInMS) * time.Millisecond
+}
+
+// ParseTimeTrialPacket decodes the time trial data sets from packet ID 14
+func ParseTimeTrialPacket(data []byte) (*PacketTimeTrialData, error) {
+	return parsePacket[PacketTimeTrialData](data, PacketTimeTrial)
+}
+
+// UnmarshalBinary decodes a Time Trial packet (ID 14) into p, reusing its storage
+func (p *PacketTimeTrialData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketTimeTrial, p)
+}
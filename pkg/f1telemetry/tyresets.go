@@ -0,0 +1,45 @@
+package f1telemetry
+
+import "time"
+
+// TyreSetsPacketSize is the size of a Tyre Sets packet (ID 12)
+const TyreSetsPacketSize = 231
+
+// MaxTyreSets is the number of tyre set slots per car (13 dry + 7 wet)
+const MaxTyreSets = 20
+
+// TyreSetData describes one tyre set allocated to a car
+type TyreSetData struct {
+	ActualTyreCompound uint8
+	VisualTyreCompound uint8
+	Wear               uint8 `unit:"%"`
+	Available          uint8
+	RecommendedSession uint8
+	LifeSpan           uint8 `unit:"laps"` // Left in this set
+	UsableLife         uint8 `unit:"laps"` // Max recommended for this compound
+	LapDeltaTime       int16 `unit:"ms"`   // Compared to the fitted set
+	Fitted             uint8
+}
+
+// PacketTyreSetsData is the decoded Tyre Sets packet (ID 12)
+type PacketTyreSetsData struct {
+	Header      PacketHeader
+	CarIdx      uint8
+	TyreSetData [MaxTyreSets]TyreSetData
+	FittedIdx   uint8
+}
+
+// LapDelta returns the lap delta to the fitted set as a duration
+func (t *TyreSetData) LapDelta() time.Duration {
+	return time.Duration(t.LapDeltaTime) * time.Millisecond
+}
+
+// ParseTyreSetsPacket decodes the tyre allocation of one car from packet ID 12
+func ParseTyreSetsPacket(data []byte) (*PacketTyreSetsData, error) {
+	return parsePacket[PacketTyreSetsData](data, PacketTyreSets)
+}
+
+// UnmarshalBinary decodes a Tyre Sets packet (ID 12) into p, reusing its storage
+func (p *PacketTyreSetsData) UnmarshalBinary(data []byte) error {
+	return unmarshalPacket(data, PacketTyreSets, p)
+}
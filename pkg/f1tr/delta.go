@@ -0,0 +1,150 @@
+package f1tr
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// DefaultKeyframeInterval is the number of packets between delta
+// keyframes
+const DefaultKeyframeInterval = 256
+
+// Delta encoded packet data starts with one of these
+const (
+	deltaKeyframe byte = 0 // The packet follows as is
+	deltaXOR      byte = 1 // The packet ID and runs from appendXORRuns follow
+)
+
+// deltaKey selects the packets a packet is encoded against
+type deltaKey struct {
+	origin uint16
+	id     uint8
+}
+
+// deltaState holds the last packet of every packet type and origin.
+// Writers and readers clear it at every keyframe interval, so the first
+// packet of each type after it is a keyframe and readers can start there.
+type deltaState struct {
+	last map[deltaKey][]byte
+}
+
+func (d *deltaState) reset() {
+	if d.last == nil {
+		d.last = make(map[deltaKey][]byte)
+	}
+	for key, data := range d.last {
+		d.last[key] = data[:0]
+	}
+}
+
+// store remembers data as the last packet for key
+func (d *deltaState) store(key deltaKey, data []byte) {
+	d.last[key] = append(d.last[key][:0], data...)
+}
+
+// encode appends the delta encoding of data to dst
+func (d *deltaState) encode(dst []byte, origin uint16, data []byte) []byte {
+	if len(data) <= 6 {
+		return append(append(dst, deltaKeyframe), data...)
+	}
+	key := deltaKey{origin: origin, id: data[6]}
+	prev := d.last[key]
+	if len(prev) != len(data) {
+		dst = append(append(dst, deltaKeyframe), data...)
+	} else {
+		dst = appendXORRuns(append(dst, deltaXOR, key.id), prev, data)
+	}
+	d.store(key, data)
+	return dst
+}
+
+// decode returns the packet data of a delta encoded record
+func (d *deltaState) decode(origin uint16, data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty delta record", ErrInvalidFile)
+	}
+
+	var packet []byte
+	switch data[0] {
+	case deltaKeyframe:
+		packet = data[1:]
+	case deltaXOR:
+		if len(data) < 2 {
+			return nil, fmt.Errorf("%w: bad delta record", ErrInvalidFile)
+		}
+		prev := d.last[deltaKey{origin: origin, id: data[1]}]
+		if len(prev) == 0 {
+			return nil, fmt.Errorf("%w: delta record without keyframe", ErrInvalidFile)
+		}
+		var err error
+		if packet, err = applyXORRuns(prev, data[2:]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: bad delta record %d", ErrInvalidFile, data[0])
+	}
+
+	if len(packet) > 6 {
+		d.store(deltaKey{origin: origin, id: packet[6]}, packet)
+	}
+	return packet, nil
+}
+
+// appendXORRuns appends the bytes of next that differ from prev, as runs
+// of a uvarint count of equal bytes, a uvarint count of differing bytes
+// and those bytes XORed with prev. Equal bytes at the end are left out.
+func appendXORRuns(dst, prev, next []byte) []byte {
+	i := 0
+	for i < len(next) {
+		start := i
+		for i < len(next) && next[i] == prev[i] {
+			i++
+		}
+		if i == len(next) {
+			break
+		}
+		zeros := i - start
+
+		// Runs of one or two equal bytes cost as much as storing them
+		start = i
+		for i < len(next) && (next[i] != prev[i] || i+2 < len(next) && (next[i+1] != prev[i+1] || next[i+2] != prev[i+2])) {
+			i++
+		}
+		dst = binary.AppendUvarint(dst, uint64(zeros))
+		dst = binary.AppendUvarint(dst, uint64(i-start))
+		for j := start; j < i; j++ {
+			dst = append(dst, next[j]^prev[j])
+		}
+	}
+	return dst
+}
+
+// applyXORRuns returns a copy of prev with runs from appendXORRuns applied
+func applyXORRuns(prev, runs []byte) ([]byte, error) {
+	bad := fmt.Errorf("%w: bad delta record", ErrInvalidFile)
+	packet := append([]byte(nil), prev...)
+	i := 0
+	for len(runs) > 0 {
+		zeros, n := binary.Uvarint(runs)
+		if n <= 0 {
+			return nil, bad
+		}
+		runs = runs[n:]
+		count, n := binary.Uvarint(runs)
+		if n <= 0 {
+			return nil, bad
+		}
+		runs = runs[n:]
+
+		if zeros > uint64(len(packet)-i) || count > uint64(len(packet)-i)-zeros || count > uint64(len(runs)) {
+			return nil, bad
+		}
+		i += int(zeros)
+		for j := 0; j < int(count); j++ {
+			packet[i+j] ^= runs[j]
+		}
+		i += int(count)
+		runs = runs[count:]
+	}
+	return packet, nil
+}
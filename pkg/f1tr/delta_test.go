@@ -0,0 +1,98 @@
+package f1tr
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestXORRuns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	prev := make([]byte, 300)
+	rng.Read(prev)
+
+	changes := map[string]func([]byte){
+		"none":        func(b []byte) {},
+		"first byte":  func(b []byte) { b[0]++ },
+		"last byte":   func(b []byte) { b[len(b)-1]++ },
+		"all":         func(b []byte) { rng.Read(b) },
+		"short gaps":  func(b []byte) { b[10]++; b[12]++; b[15]++; b[17]++ },
+		"long gap":    func(b []byte) { b[10]++; b[200]++ },
+		"alternating": func(b []byte) { rng.Read(b[:150]) },
+		"sparse": func(b []byte) {
+			for i := range b {
+				if rng.Intn(8) == 0 {
+					b[i] = byte(rng.Intn(256))
+				}
+			}
+		},
+	}
+	for name, change := range changes {
+		next := append([]byte(nil), prev...)
+		change(next)
+
+		runs := appendXORRuns(nil, prev, next)
+		got, err := applyXORRuns(prev, runs)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(got, next) {
+			t.Errorf("%s: decoded packet differs", name)
+		}
+		if name == "none" && len(runs) != 0 {
+			t.Errorf("unchanged packet encoded in %d bytes", len(runs))
+		}
+	}
+}
+
+func TestXORRunsDamaged(t *testing.T) {
+	prev := make([]byte, 50)
+	for _, runs := range [][]byte{
+		{0x80},          // Truncated uvarint
+		{0, 0x80},       // Truncated count
+		{60, 1, 0},      // Past the end
+		{0, 5, 1, 2},    // Fewer bytes than the count
+		{49, 2, 1, 1},   // Run past the end
+		{0xFF, 0xFF, 1}, // Huge count of equal bytes
+	} {
+		if _, err := applyXORRuns(prev, runs); err == nil {
+			t.Errorf("runs %v accepted", runs)
+		}
+	}
+}
+
+func TestDeltaState(t *testing.T) {
+	var w, r deltaState
+	w.reset()
+	r.reset()
+
+	packets := [][]byte{
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 5, 6, 7, 8, 10},       // Same type
+		{1, 2, 3, 4, 5, 6, 3, 8, 10},       // Another type
+		{1, 2, 3, 4, 5, 6, 7, 8, 10, 11},   // Another size
+		{1, 2},                             // No header
+		{1, 2, 3, 4, 5, 6, 7, 8, 10, 0xFF}, // Same type and size
+	}
+	var buf []byte
+	for i, p := range packets {
+		for _, origin := range []uint16{1, 2} {
+			buf = w.encode(buf[:0], origin, p)
+			got, err := r.decode(origin, buf)
+			if err != nil {
+				t.Fatalf("packet %d from %d: %v", i, origin, err)
+			}
+			if !bytes.Equal(got, p) {
+				t.Fatalf("packet %d from %d decoded as %v", i, origin, got)
+			}
+		}
+	}
+
+	// After a reset the reader can not decode without a keyframe
+	w.encode(buf[:0], 1, packets[0])
+	buf = w.encode(buf[:0], 1, packets[1])
+	r.reset()
+	if _, err := r.decode(1, buf); err == nil {
+		t.Error("delta decoded without keyframe")
+	}
+}
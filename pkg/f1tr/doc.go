@@ -0,0 +1,35 @@
+// Package f1tr reads and writes .f1tr telemetry recordings.
+//
+// A recording is a file header followed by the raw UDP packets as they
+// were received, each with its arrival time and the endpoint and sender it
+// came from. Writer appends packets to any io.Writer; Reader returns them
+// in order from any io.Reader.
+//
+// Closing a Writer adds a seek index and a Footer with the totals. Readers
+// of an io.ReadSeeker such as an *os.File use them to seek by time, frame
+// or packet type; files without an index are scanned instead.
+//
+// NewWriterLevel compresses the packets in gzip blocks that each decode on
+// their own; Reader reads compressed and uncompressed files alike.
+// NewWriterOptions can also delta encode packets against the previous
+// one of the same type, which Reader undoes exactly.
+//
+// Writer.WriteMetadata stores a description of the session, which
+// Reader.Metadata reads back without going through the packets.
+//
+// Every record carries a checksum. Reader ends cleanly at the torn last
+// record an interrupted Writer leaves and reports other damage with
+// ErrChecksum; Recover rewrites the intact records of a damaged file.
+//
+// # Compatibility
+//
+// This package follows semantic versioning with the module it belongs to.
+// Within a major version:
+//
+//   - Every recording written by an earlier release stays readable.
+//   - Writer may start using new format versions or header flags. Older
+//     releases reject such files with ErrUnsupported instead of
+//     misreading them.
+//   - Exported identifiers are not removed and fields may be added to
+//     Header and Record.
+package f1tr
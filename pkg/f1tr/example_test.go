@@ -0,0 +1,91 @@
+package f1tr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1tr"
+)
+
+func Example() {
+	var file bytes.Buffer
+
+	w, err := f1tr.NewWriter(&file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		data := make([]byte, f1telemetry.PacketHeaderSize)
+		binary.LittleEndian.PutUint16(data, f1telemetry.PacketFormat2025)
+		data[6] = uint8(f1telemetry.PacketEvent)
+		err := w.WritePacket(&f1telemetry.RecordedPacket{
+			Timestamp: time.Unix(1700000000, int64(i)*int64(time.Millisecond)),
+			Data:      data,
+			Origin: f1telemetry.Origin{
+				Endpoint: "0.0.0.0:20777",
+				Source:   netip.MustParseAddrPort("192.168.1.20:50123"),
+			},
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	r, err := f1tr.NewReader(&file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(rec.Timestamp.UTC().Format("15:04:05.000"), len(rec.Data), rec.Origin)
+	}
+	// Output:
+	// 22:13:20.000 29 192.168.1.20:50123 via 0.0.0.0:20777
+	// 22:13:20.001 29 192.168.1.20:50123 via 0.0.0.0:20777
+	// 22:13:20.002 29 192.168.1.20:50123 via 0.0.0.0:20777
+}
+
+func ExampleNewReader() {
+	file, err := os.Open("recordings/session.f1tr")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	r, err := f1tr.NewReader(file)
+	if errors.Is(err, f1tr.ErrInvalidFile) {
+		log.Fatal("not a recording")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		rec, err := r.Next()
+		if err != nil {
+			break
+		}
+		packet, err := rec.Packet()
+		if err != nil {
+			continue
+		}
+		fmt.Println(packet.Timestamp, f1telemetry.GetPacketTypeName(packet.Header.PacketID))
+	}
+}
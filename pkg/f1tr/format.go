@@ -0,0 +1,192 @@
+package f1tr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// File layout, all integers little-endian:
+//
+//	Header   "F1TR", version uint16, created int64 (Unix ns), flags uint32, 28 reserved bytes
+//	Records  timestamp int64 (Unix ns), size uint32, origin uint16 (FlagOrigins only),
+//	         checksum uint32 (FlagChecksums only), data
+//
+// The top bits of size mark records that are not packets; the rest is the
+// length of data. With FlagOrigins an origin is defined once, before its
+// first packet, by a recordOrigin record whose data is the endpoint and
+// the source address separated by a zero byte. Origin ID 0 is the unknown
+// origin.
+//
+// Version 2 adds a seek index. Every indexEntryPackets packets start an
+// index entry, and every indexBlockEntries entries are written out in a
+// recordIndex record (see indexBlock). Closing the file writes the last
+// block and a recordFooter record (see footer) that ends with its own
+// offset and footerMagic, so readers find it from the end of the file.
+// Version 1 files have neither and are read from start to end.
+//
+// Version 3 files carry origins, checksums and sessions (FlagOrigins,
+// FlagChecksums and FlagSessions), which change the layout of records and
+// index entries, so readers that only know version 2 refuse them instead
+// of misreading them. Older files are read by the flags they have.
+//
+// With FlagMetadata the session is described by recordMetadata records
+// holding Metadata as JSON, and the footer has the offset of the last one
+// before its own offset. Writers that know the session when they start
+// put a first one right after the header.
+//
+// With FlagCompressed the packet and origin records of every index entry
+// are gzip compressed together into one recordBlock record, which the
+// entry points to. Index, metadata and footer records stay uncompressed.
+//
+// With FlagDelta the data of a packet record is a keyframe marker and the
+// packet, or a delta marker, the packet ID and the runs of bytes that
+// changed since the last packet with that ID and origin (see deltaState).
+// The first packet of each type in an index entry is always a keyframe.
+//
+// With FlagChecksums every record carries the CRC-32C of the fields before
+// the checksum and of its data. Records inside a compressed block are
+// covered by the checksum of the block.
+//
+// With FlagSessions index entries also hold the session UID, and an entry
+// only covers packets of one session. Frame numbers restart with every
+// session, so they are only ordered within one.
+
+const (
+	// Magic starts every recording
+	Magic = "F1TR"
+
+	// Version is the file format version written by Writer
+	Version = 3
+
+	// HeaderSize is the size of the file header
+	HeaderSize = 46
+)
+
+// Record kinds, set in the top bits of the record size
+const (
+	recordOrigin   uint32 = 1 << 31
+	recordIndex    uint32 = 1 << 30
+	recordFooter   uint32 = 1 << 29
+	recordMetadata uint32 = 1 << 28
+	recordBlock    uint32 = 1 << 27
+
+	recordKinds = recordOrigin | recordIndex | recordFooter | recordMetadata | recordBlock
+)
+
+// Header flags
+const (
+	// FlagOrigins marks files whose records carry an origin ID
+	FlagOrigins uint32 = 1 << 0
+
+	// FlagMetadata marks files that may hold session metadata
+	FlagMetadata uint32 = 1 << 1
+
+	// FlagCompressed marks files whose packets are stored in compressed
+	// blocks
+	FlagCompressed uint32 = 1 << 2
+
+	// FlagDelta marks files whose packets are delta encoded
+	FlagDelta uint32 = 1 << 3
+
+	// FlagChecksums marks files whose records carry a checksum
+	FlagChecksums uint32 = 1 << 4
+
+	// FlagSessions marks files whose index entries hold a session UID
+	FlagSessions uint32 = 1 << 5
+)
+
+// knownFlags are the flags this package can read
+const knownFlags = FlagOrigins | FlagMetadata | FlagCompressed | FlagDelta | FlagChecksums | FlagSessions
+
+var (
+	// ErrInvalidFile is returned for data that is not a recording
+	ErrInvalidFile = errors.New("not an f1tr recording")
+	// ErrUnsupported is returned for recordings that use a newer format
+	// or features this package does not know
+	ErrUnsupported = errors.New("unsupported f1tr recording")
+	// ErrClosed is returned when writing to a closed Writer
+	ErrClosed = errors.New("f1tr writer is closed")
+	// ErrNotSeekable is returned when seeking a Reader whose source is
+	// not an io.Seeker
+	ErrNotSeekable = errors.New("f1tr reader is not seekable")
+	// ErrChecksum is returned for damaged records that are not at the end
+	// of the recording. Recover salvages the records around them.
+	ErrChecksum = errors.New("f1tr record checksum mismatch")
+)
+
+// crcTable is the CRC-32C table for record checksums
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// recordChecksum returns the checksum of a record from the fields before
+// the checksum and its data
+func recordChecksum(fields, data []byte) uint32 {
+	return crc32.Update(crc32.Checksum(fields, crcTable), crcTable, data)
+}
+
+// maxRecordHeaderLen is the largest size of a record before its data
+const maxRecordHeaderLen = recordHeaderSize + 4
+
+// maxRecordSize is the largest record data Writer writes. Larger sizes
+// can only be read from damaged records.
+const maxRecordSize = 4 << 20
+
+// recordHeaderLen returns the size of a record before its data in files
+// with the given flags
+func recordHeaderLen(flags uint32) int {
+	switch {
+	case flags&FlagOrigins == 0:
+		return 12
+	case flags&FlagChecksums == 0:
+		return recordHeaderSize
+	}
+	return maxRecordHeaderLen
+}
+
+// Header is the file header of a recording
+type Header struct {
+	Version uint16
+	Created time.Time
+	Flags   uint32
+}
+
+// readHeader reads and checks a file header
+func readHeader(r io.Reader) (Header, error) {
+	var buf [HeaderSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return Header{}, ErrInvalidFile
+		}
+		return Header{}, err
+	}
+	if string(buf[0:4]) != Magic {
+		return Header{}, ErrInvalidFile
+	}
+
+	h := Header{
+		Version: binary.LittleEndian.Uint16(buf[4:]),
+		Created: time.Unix(0, int64(binary.LittleEndian.Uint64(buf[6:]))),
+		Flags:   binary.LittleEndian.Uint32(buf[14:]),
+	}
+	if h.Version < 1 || h.Version > Version {
+		return Header{}, fmt.Errorf("%w: version %d", ErrUnsupported, h.Version)
+	}
+	if h.Flags&^knownFlags != 0 {
+		return Header{}, fmt.Errorf("%w: flags %#x", ErrUnsupported, h.Flags)
+	}
+	return h, nil
+}
+
+// writeHeader writes a file header
+func writeHeader(w io.Writer, h Header) error {
+	var buf [HeaderSize]byte
+	copy(buf[0:4], Magic)
+	binary.LittleEndian.PutUint16(buf[4:], h.Version)
+	binary.LittleEndian.PutUint64(buf[6:], uint64(h.Created.UnixNano()))
+	binary.LittleEndian.PutUint32(buf[14:], h.Flags)
+	_, err := w.Write(buf[:])
+	return err
+}
@@ -0,0 +1,206 @@
+package f1tr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+const (
+	// indexEntryPackets is the number of packets covered by an index entry
+	indexEntryPackets = 64
+
+	// indexBlockEntries is the number of entries written per index block
+	indexBlockEntries = 64
+
+	// indexEntrySize is the encoded size of an indexEntry, 8 more with
+	// FlagSessions
+	indexEntrySize = 24
+
+	// footerMagic ends a finished recording
+	footerMagic = "F1TREND\x00"
+
+	// footerTrailerSize is the footer offset and footerMagic at the end of a file
+	footerTrailerSize = 16
+)
+
+// indexEntry locates a run of up to indexEntryPackets packets. The frame
+// and session are those of the first packet with a valid header; runs
+// without one have no types.
+type indexEntry struct {
+	offset    int64  // Offset of the first packet record
+	timestamp int64  // Timestamp of the first packet, Unix ns
+	frame     uint32 // OverallFrameIdentifier
+	types     uint32 // Bit n set if the run holds a packet with ID n
+	session   uint64 // SessionUID, with FlagSessions
+}
+
+// indexEntryLen returns the encoded size of an indexEntry in files with
+// the given flags
+func indexEntryLen(flags uint32) int {
+	if flags&FlagSessions != 0 {
+		return indexEntrySize + 8
+	}
+	return indexEntrySize
+}
+
+// indexBlock is the data of a recordIndex record:
+//
+//	prev     int64, offset of the previous index block, -1 for the first
+//	origins  uint16 count, then per origin a uint16 length and its definition
+//	entries  uint32 count, then the entries
+//
+// Every block repeats all origins defined so far, so a reader that jumps
+// into the file knows them without reading the records before.
+type indexBlock struct {
+	prev    int64
+	origins []f1telemetry.Origin // Without the unknown origin 0
+	entries []indexEntry
+}
+
+func (b *indexBlock) encode(flags uint32) []byte {
+	data := binary.LittleEndian.AppendUint64(nil, uint64(b.prev))
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(b.origins)))
+	for _, origin := range b.origins {
+		definition := encodeOrigin(origin)
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(definition)))
+		data = append(data, definition...)
+	}
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(b.entries)))
+	for _, e := range b.entries {
+		data = binary.LittleEndian.AppendUint64(data, uint64(e.offset))
+		data = binary.LittleEndian.AppendUint64(data, uint64(e.timestamp))
+		data = binary.LittleEndian.AppendUint32(data, e.frame)
+		data = binary.LittleEndian.AppendUint32(data, e.types)
+		if flags&FlagSessions != 0 {
+			data = binary.LittleEndian.AppendUint64(data, e.session)
+		}
+	}
+	return data
+}
+
+func (b *indexBlock) decode(data []byte, flags uint32) error {
+	bad := fmt.Errorf("%w: bad index block", ErrInvalidFile)
+	if len(data) < 10 {
+		return bad
+	}
+	b.prev = int64(binary.LittleEndian.Uint64(data))
+	n := int(binary.LittleEndian.Uint16(data[8:]))
+	data = data[10:]
+
+	b.origins = make([]f1telemetry.Origin, 0, n)
+	for i := 0; i < n; i++ {
+		if len(data) < 2 {
+			return bad
+		}
+		size := int(binary.LittleEndian.Uint16(data))
+		if len(data) < 2+size {
+			return bad
+		}
+		b.origins = append(b.origins, parseOrigin(data[2:2+size]))
+		data = data[2+size:]
+	}
+
+	if len(data) < 4 {
+		return bad
+	}
+	n = int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	size := indexEntryLen(flags)
+	if len(data) != n*size {
+		return bad
+	}
+	b.entries = make([]indexEntry, n)
+	for i := range b.entries {
+		e := data[i*size:]
+		b.entries[i] = indexEntry{
+			offset:    int64(binary.LittleEndian.Uint64(e)),
+			timestamp: int64(binary.LittleEndian.Uint64(e[8:])),
+			frame:     binary.LittleEndian.Uint32(e[16:]),
+			types:     binary.LittleEndian.Uint32(e[20:]),
+		}
+		if flags&FlagSessions != 0 {
+			b.entries[i].session = binary.LittleEndian.Uint64(e[24:])
+		}
+	}
+	return nil
+}
+
+// Footer holds the totals of a finished recording
+type Footer struct {
+	Packets       uint64
+	Start         time.Time // Timestamp of the first packet
+	End           time.Time // Timestamp of the last packet
+	PacketsByType []uint64  // Packet count by packet ID
+}
+
+// Duration returns the time between the first and the last packet
+func (f *Footer) Duration() time.Duration {
+	return f.End.Sub(f.Start)
+}
+
+// footer is the data of a recordFooter record:
+//
+//	lastIndex  int64, offset of the last index block, -1 if none
+//	packets    uint64
+//	start, end int64, Unix ns
+//	types      uint16 count, then a uint64 packet count per packet ID
+//	metadata   int64, offset of the last metadata record, -1 if none (FlagMetadata only)
+//	offset     int64, offset of the footer record itself
+//	magic      footerMagic
+type footer struct {
+	Footer
+	lastIndex int64
+	metadata  int64
+}
+
+func (f *footer) encode(offset int64, flags uint32) []byte {
+	data := binary.LittleEndian.AppendUint64(nil, uint64(f.lastIndex))
+	data = binary.LittleEndian.AppendUint64(data, f.Packets)
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.Start.UnixNano()))
+	data = binary.LittleEndian.AppendUint64(data, uint64(f.End.UnixNano()))
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(f.PacketsByType)))
+	for _, n := range f.PacketsByType {
+		data = binary.LittleEndian.AppendUint64(data, n)
+	}
+	if flags&FlagMetadata != 0 {
+		data = binary.LittleEndian.AppendUint64(data, uint64(f.metadata))
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(offset))
+	return append(data, footerMagic...)
+}
+
+func (f *footer) decode(data []byte, flags uint32) error {
+	bad := fmt.Errorf("%w: bad footer", ErrInvalidFile)
+	trailer := footerTrailerSize
+	if flags&FlagMetadata != 0 {
+		trailer += 8
+	}
+	if len(data) < 34+trailer {
+		return bad
+	}
+	f.lastIndex = int64(binary.LittleEndian.Uint64(data))
+	f.Packets = binary.LittleEndian.Uint64(data[8:])
+	f.Start = time.Unix(0, int64(binary.LittleEndian.Uint64(data[16:])))
+	f.End = time.Unix(0, int64(binary.LittleEndian.Uint64(data[24:])))
+	n := int(binary.LittleEndian.Uint16(data[32:]))
+	if len(data) != 34+n*8+trailer {
+		return bad
+	}
+	f.PacketsByType = make([]uint64, n)
+	for i := range f.PacketsByType {
+		f.PacketsByType[i] = binary.LittleEndian.Uint64(data[34+i*8:])
+	}
+	f.metadata = -1
+	if flags&FlagMetadata != 0 {
+		f.metadata = int64(binary.LittleEndian.Uint64(data[34+n*8:]))
+	}
+	return nil
+}
+
+// encodeOrigin returns the definition of an origin
+func encodeOrigin(o f1telemetry.Origin) []byte {
+	return []byte(o.Endpoint + "\x00" + o.Source.String())
+}
@@ -0,0 +1,95 @@
+package f1tr
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Metadata describes the session in a recording. It is stored as JSON in
+// recordMetadata records, so fields can be added without a new format
+// version.
+type Metadata struct {
+	GameVersion     string        `json:"gameVersion,omitempty"` // Game year and version, like "25 1.12"
+	PacketFormat    uint16        `json:"packetFormat,omitempty"`
+	SessionUID      uint64        `json:"sessionUID,omitempty"`
+	Track           string        `json:"track,omitempty"`
+	SessionType     string        `json:"sessionType,omitempty"`
+	Weather         string        `json:"weather,omitempty"`
+	Participants    []Participant `json:"participants,omitempty"`
+	PlayerCarIndex  uint8         `json:"playerCarIndex"`
+	RecorderVersion string        `json:"recorderVersion,omitempty"`
+	Host            string        `json:"host,omitempty"`
+	Tags            []string      `json:"tags,omitempty"`
+	Updated         time.Time     `json:"updated"` // Set by WriteMetadata
+}
+
+// Participant is a driver in the session
+type Participant struct {
+	Name        string `json:"name"`
+	TeamID      uint8  `json:"teamID"`
+	RaceNumber  uint8  `json:"raceNumber"`
+	Nationality uint8  `json:"nationality"`
+	AI          bool   `json:"ai,omitempty"`
+}
+
+// WriteMetadata appends the session metadata. It can be written any
+// number of times; the last one written is the one readers see. Writing
+// it before the first packet makes it readable from unfinished files.
+func (w *Writer) WriteMetadata(m *Metadata) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	// Index entries of compressed files start at a block, so the
+	// current one is written first
+	if w.run > 0 && w.flags&FlagCompressed != 0 {
+		if err := w.endRun(w.footer.End.UnixNano()); err != nil {
+			return err
+		}
+	}
+
+	copied := *m
+	copied.Updated = time.Now()
+	data, err := json.Marshal(&copied)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	offset := w.size
+	if err := w.writeRecord(copied.Updated.UnixNano(), uint32(len(data))|recordMetadata, 0, data); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	w.footer.metadata = offset
+	return nil
+}
+
+// Metadata returns the session metadata, or nil if the recording has
+// none. Finished recordings point to the last metadata written; others
+// only have metadata if it was written before the first packet.
+func (r *Reader) Metadata() (*Metadata, error) {
+	if err := r.loadIndex(); err != nil {
+		return nil, err
+	}
+	if r.metadata < 0 {
+		return nil, nil
+	}
+
+	// Reading the record moves the source, come back for Next
+	defer r.restore()()
+
+	end, err := r.end()
+	if err != nil {
+		return nil, err
+	}
+	kind, data, err := r.readRecordAt(r.metadata, end)
+	if err != nil || kind != recordMetadata {
+		return nil, err
+	}
+
+	m := new(Metadata)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%w: bad metadata: %v", ErrInvalidFile, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,683 @@
+package f1tr
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// Record is one packet read from a recording
+type Record struct {
+	Timestamp time.Time
+	Origin    f1telemetry.Origin // Zero if the recording has no origins
+	Data      []byte
+}
+
+// Packet parses the packet header of the record
+func (r *Record) Packet() (*f1telemetry.RecordedPacket, error) {
+	header, err := f1telemetry.ParseHeader(r.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &f1telemetry.RecordedPacket{
+		Timestamp: r.Timestamp,
+		Data:      r.Data,
+		Header:    *header,
+		Origin:    r.Origin,
+	}, nil
+}
+
+// Reader reads packets from a recording. It is not safe for concurrent use.
+//
+// Readers of an io.ReadSeeker can also seek. Finished files of version 2
+// and later are seeked through their index; others are scanned from the
+// start.
+type Reader struct {
+	r       *bufio.Reader
+	header  Header
+	origins []f1telemetry.Origin // Origins defined so far, by ID
+	offset  int64                // File offset of the next record
+	pending *Record              // Record found by the last seek
+	block   []byte               // Unread records of a compressed block
+	zr      *gzip.Reader
+	delta   *deltaState // Nil unless FlagDelta is set
+	torn    bool        // Set when the last record was cut short
+	resync  bool        // Set after damage, until the next seek
+
+	rs       io.ReadSeeker // Nil if the source can not seek
+	base     int64         // Source offset of the file header
+	indexed  bool          // Set once the index has been loaded
+	index    []indexEntry
+	footer   *Footer
+	metadata int64 // Offset of the metadata record, -1 if none
+}
+
+// NewReader reads the file header from r and returns a Reader for the
+// packets. It returns ErrInvalidFile if r does not hold a recording.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{origins: []f1telemetry.Origin{{}}, metadata: -1}
+	if rs, ok := r.(io.ReadSeeker); ok {
+		if base, err := rs.Seek(0, io.SeekCurrent); err == nil {
+			reader.rs = rs
+			reader.base = base
+		}
+	}
+
+	reader.r = bufio.NewReader(r)
+	header, err := readHeader(reader.r)
+	if err != nil {
+		return nil, err
+	}
+	reader.header = header
+	reader.offset = HeaderSize
+	if header.Flags&FlagDelta != 0 {
+		reader.delta = &deltaState{}
+		reader.delta.reset()
+	}
+	return reader, nil
+}
+
+// Header returns the file header
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next returns the next packet. It returns io.EOF after the last one.
+//
+// A last record that was not completely written, as a crash leaves it,
+// also ends the recording with io.EOF; Torn reports it. Damaged records
+// before the end return ErrChecksum once, after which Next continues with
+// the next intact record.
+func (r *Reader) Next() (*Record, error) {
+	if rec := r.pending; rec != nil {
+		r.pending = nil
+		return rec, nil
+	}
+
+	n := recordHeaderLen(r.header.Flags)
+
+	for {
+		// Records of the current compressed block come first
+		if len(r.block) > 0 {
+			rec, err := r.nextInBlock()
+			if rec != nil || err != nil {
+				return rec, err
+			}
+			continue
+		}
+
+		var buf [maxRecordHeaderLen]byte
+		if read, err := io.ReadFull(r.r, buf[:n]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return nil, r.damaged(buf[:read])
+			}
+			return nil, err
+		}
+		timestamp := int64(binary.LittleEndian.Uint64(buf[0:]))
+		size := binary.LittleEndian.Uint32(buf[8:])
+		var originID uint16
+		if n >= recordHeaderSize {
+			originID = binary.LittleEndian.Uint16(buf[12:])
+		}
+		length := int(size &^ recordKinds)
+		if length > maxRecordSize {
+			return nil, r.damaged(buf[:n])
+		}
+
+		data := make([]byte, length)
+		if read, err := io.ReadFull(r.r, data); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, r.damaged(append(buf[:n:n], data[:read]...))
+			}
+			return nil, err
+		}
+		if n > recordHeaderSize && binary.LittleEndian.Uint32(buf[recordHeaderSize:]) != recordChecksum(buf[:recordHeaderSize], data) {
+			return nil, r.damaged(append(buf[:n:n], data...))
+		}
+		r.offset += int64(n + length)
+
+		// The index and metadata are read on demand
+		if size&(recordIndex|recordFooter|recordMetadata) != 0 {
+			if size&recordFooter != 0 {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		if size&recordBlock != 0 {
+			if err := r.decompress(data); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		rec, err := r.record(timestamp, size, originID, data)
+		if rec != nil || err != nil {
+			return rec, err
+		}
+	}
+}
+
+// Torn reports whether the recording ended in a record that was not
+// completely written
+func (r *Reader) Torn() bool {
+	return r.torn
+}
+
+// damaged is called for the record at r.offset when it is cut short or
+// fails its checksum, with the bytes of it read so far. If no intact
+// record follows it is the torn end a crashed writer leaves, which ends
+// the recording. Otherwise it returns ErrChecksum and moves to the next
+// intact record.
+func (r *Reader) damaged(read []byte) error {
+	damagedAt := r.offset
+
+	// The scan reads ahead, so it continues as the source of Next
+	br := bufio.NewReaderSize(io.MultiReader(bytes.NewReader(read[1:]), r.r), maxRecordSize+maxRecordHeaderLen)
+	r.r = br
+	r.offset++
+	r.block = nil
+	r.resync = true
+
+	// Without checksums only a packet is evidence of an intact record
+	var formats []uint16
+	if r.header.Flags&FlagChecksums == 0 {
+		formats = f1telemetry.SupportedFormats()
+	}
+	for {
+		record := intactRecord(br, r.header.Flags, formats)
+		if record != nil && (formats == nil || binary.LittleEndian.Uint32(record[8:])&recordKinds == 0) {
+			break
+		}
+		if _, err := br.Discard(1); err != nil {
+			if err != io.EOF {
+				return err
+			}
+			// Finished files were not torn by a crash
+			if trailer, err := r.readTrailer(); err == nil && string(trailer[8:]) == footerMagic {
+				return fmt.Errorf("%w at offset %d", ErrChecksum, damagedAt)
+			}
+			r.torn = true
+			return io.EOF
+		}
+		r.offset++
+	}
+
+	// Packets after the damage can not be decoded against those before
+	if r.delta != nil {
+		r.delta.reset()
+	}
+	return fmt.Errorf("%w at offset %d", ErrChecksum, damagedAt)
+}
+
+// intactRecord returns the record at the start of br if it is intact,
+// without consuming it. Files without checksums only have their packets
+// checked, against formats. br must be able to buffer the largest record.
+func intactRecord(br *bufio.Reader, flags uint32, formats []uint16) []byte {
+	n := recordHeaderLen(flags)
+	head, err := br.Peek(n)
+	if err != nil {
+		return nil
+	}
+	size := binary.LittleEndian.Uint32(head[8:])
+	kind := size & recordKinds
+	length := int(size &^ recordKinds)
+	if kind&(kind-1) != 0 || length > maxRecordSize {
+		return nil
+	}
+	record, err := br.Peek(n + length)
+	if err != nil {
+		return nil
+	}
+
+	if flags&FlagChecksums != 0 {
+		if binary.LittleEndian.Uint32(record[recordHeaderSize:]) != recordChecksum(record[:recordHeaderSize], record[n:]) {
+			return nil
+		}
+	} else if kind == 0 && !plausiblePacket(record[n:], flags, formats) {
+		return nil
+	}
+	return record
+}
+
+// plausiblePacket reports whether the data of a packet record starts with
+// a packet header of one of formats
+func plausiblePacket(data []byte, flags uint32, formats []uint16) bool {
+	if flags&FlagDelta != 0 {
+		if len(data) == 0 || data[0] != deltaKeyframe {
+			// Delta records hold no header to check
+			return len(data) > 1 && data[0] == deltaXOR
+		}
+		data = data[1:]
+	}
+	var h f1telemetry.PacketHeader
+	return h.UnmarshalBinary(data) == nil && slices.Contains(formats, h.PacketFormat)
+}
+
+// nextInBlock reads the next record of the current block. It returns
+// neither a record nor an error for origin definitions.
+func (r *Reader) nextInBlock() (*Record, error) {
+	timestamp, size, originID, data, rest, ok := splitRecord(r.block)
+	if !ok {
+		return nil, fmt.Errorf("%w: bad record in block", ErrInvalidFile)
+	}
+	r.block = rest
+	return r.record(timestamp, size, originID, data)
+}
+
+// splitRecord splits the first record off the records of a block. Records
+// keep their data, so it is not copied out of the block.
+func splitRecord(block []byte) (timestamp int64, size uint32, originID uint16, data, rest []byte, ok bool) {
+	if len(block) < recordHeaderSize {
+		return 0, 0, 0, nil, nil, false
+	}
+	timestamp = int64(binary.LittleEndian.Uint64(block[0:]))
+	size = binary.LittleEndian.Uint32(block[8:])
+	originID = binary.LittleEndian.Uint16(block[12:])
+	end := recordHeaderSize + int(size&^recordKinds)
+	if size&^recordOrigin&recordKinds != 0 || len(block) < end {
+		return 0, 0, 0, nil, nil, false
+	}
+	return timestamp, size, originID, block[recordHeaderSize:end:end], block[end:], true
+}
+
+// decompress makes the records of a recordBlock the next to be read
+func (r *Reader) decompress(data []byte) error {
+	if r.header.Flags&FlagCompressed == 0 {
+		return fmt.Errorf("%w: block in uncompressed file", ErrInvalidFile)
+	}
+	var err error
+	if r.zr == nil {
+		r.zr, err = gzip.NewReader(bytes.NewReader(data))
+	} else {
+		err = r.zr.Reset(bytes.NewReader(data))
+	}
+	if err == nil {
+		r.block, err = io.ReadAll(r.zr)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: bad block: %v", ErrInvalidFile, err)
+	}
+	return nil
+}
+
+// record returns the packet of a packet record. Origin definitions are
+// added to the known origins and return neither a record nor an error.
+func (r *Reader) record(timestamp int64, size uint32, originID uint16, data []byte) (*Record, error) {
+	if size&recordOrigin != 0 {
+		// Origins known from the index are defined again
+		// when a seek lands before their definition
+		switch {
+		case int(originID) < len(r.origins):
+		case int(originID) == len(r.origins):
+			r.origins = append(r.origins, parseOrigin(data))
+		case r.resync:
+			// Definitions before it were damaged
+			for int(originID) > len(r.origins) {
+				r.origins = append(r.origins, f1telemetry.Origin{})
+			}
+			r.origins = append(r.origins, parseOrigin(data))
+		default:
+			return nil, fmt.Errorf("%w: origin %d defined out of order", ErrInvalidFile, originID)
+		}
+		return nil, nil
+	}
+
+	if r.delta != nil {
+		var err error
+		if data, err = r.delta.decode(originID, data); err != nil {
+			if r.resync {
+				// Encoded against a damaged packet
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+
+	rec := &Record{Timestamp: time.Unix(0, timestamp), Data: data}
+	if int(originID) < len(r.origins) {
+		rec.Origin = r.origins[originID]
+	}
+	return rec, nil
+}
+
+// SeekTime moves to the first packet at or after t, so that Next returns
+// it. Seeking past the last packet leaves the Reader at the end.
+func (r *Reader) SeekTime(t time.Time) error {
+	if err := r.loadIndex(); err != nil {
+		return err
+	}
+	ns := t.UnixNano()
+	i := sort.Search(len(r.index), func(i int) bool { return r.index[i].timestamp >= ns })
+	return r.seek(r.entryOffset(i-1), func(rec *Record) bool {
+		return !rec.Timestamp.Before(t)
+	})
+}
+
+// SeekFrame moves to the first packet of the first session in the
+// recording whose OverallFrameIdentifier is at or after frame, so that
+// Next returns it. Frames restart with every session; SeekSessionFrame
+// seeks in the others.
+func (r *Reader) SeekFrame(frame uint32) error {
+	session, err := r.firstSession()
+	if err != nil {
+		return err
+	}
+	return r.SeekSessionFrame(session, frame)
+}
+
+// SeekSessionFrame moves to the first packet of the session with the
+// given SessionUID whose OverallFrameIdentifier is at or after frame, so
+// that Next returns it. If there is none it leaves the Reader at the end.
+//
+// Only indexes of files with FlagSessions tell sessions apart; other
+// files are scanned from the start.
+func (r *Reader) SeekSessionFrame(session uint64, frame uint32) error {
+	if err := r.loadIndex(); err != nil {
+		return err
+	}
+
+	// Frames are ordered within the entries of a session. Packets of a
+	// frame can end the entry before the first one starting with it.
+	start := -1
+	if r.header.Flags&FlagSessions != 0 {
+		start = len(r.index) - 1
+		found := false
+		for i, e := range r.index {
+			if e.types == 0 || e.session != session {
+				continue
+			}
+			if e.frame >= frame {
+				if !found {
+					start = i
+				}
+				break
+			}
+			start, found = i, true
+		}
+	}
+
+	return r.seek(r.entryOffset(start), func(rec *Record) bool {
+		var h f1telemetry.PacketHeader
+		return h.UnmarshalBinary(rec.Data) == nil && h.SessionUID == session && h.OverallFrameIdentifier >= frame
+	})
+}
+
+// firstSession returns the SessionUID of the first valid packet
+func (r *Reader) firstSession() (uint64, error) {
+	if err := r.loadIndex(); err != nil {
+		return 0, err
+	}
+	if r.header.Flags&FlagSessions != 0 {
+		for _, e := range r.index {
+			if e.types != 0 {
+				return e.session, nil
+			}
+		}
+	}
+
+	var session uint64
+	err := r.seek(HeaderSize, func(rec *Record) bool {
+		var h f1telemetry.PacketHeader
+		if h.UnmarshalBinary(rec.Data) != nil {
+			return false
+		}
+		session = h.SessionUID
+		return true
+	})
+	return session, err
+}
+
+// SeekPacket moves to the first packet with the given packet ID at or
+// after t, so that Next returns it. Index entries without such a packet
+// are skipped without reading them.
+func (r *Reader) SeekPacket(id uint8, t time.Time) error {
+	if err := r.loadIndex(); err != nil {
+		return err
+	}
+	ns := t.UnixNano()
+	i := max(sort.Search(len(r.index), func(i int) bool { return r.index[i].timestamp >= ns })-1, 0)
+	if id < 32 {
+		for i < len(r.index)-1 && r.index[i].types&(1<<id) == 0 {
+			i++
+		}
+	}
+	return r.seek(r.entryOffset(i), func(rec *Record) bool {
+		return len(rec.Data) > 6 && rec.Data[6] == id && !rec.Timestamp.Before(t)
+	})
+}
+
+// Footer returns the totals of a finished recording of version 2 or
+// later, or nil if the recording has no footer
+func (r *Reader) Footer() (*Footer, error) {
+	if err := r.loadIndex(); err != nil {
+		return nil, err
+	}
+	return r.footer, nil
+}
+
+// entryOffset returns the offset of index entry i, or of the first record
+// if there is no such entry
+func (r *Reader) entryOffset(i int) int64 {
+	if i < 0 || i >= len(r.index) {
+		return HeaderSize
+	}
+	return r.index[i].offset
+}
+
+// seek moves to offset and reads up to the first packet matching match
+func (r *Reader) seek(offset int64, match func(*Record) bool) error {
+	if err := r.moveTo(offset); err != nil {
+		return err
+	}
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if match(rec) {
+			r.pending = rec
+			return nil
+		}
+	}
+}
+
+// moveTo makes Next continue reading at offset
+func (r *Reader) moveTo(offset int64) error {
+	if _, err := r.rs.Seek(r.base+offset, io.SeekStart); err != nil {
+		return err
+	}
+	r.r.Reset(r.rs)
+	r.offset = offset
+	r.pending = nil
+	r.block = nil
+	r.resync = false
+	if r.delta != nil {
+		r.delta.reset()
+	}
+	return nil
+}
+
+// loadIndex reads the footer and the index blocks from the end of the
+// file. Files without a footer get an empty index.
+func (r *Reader) loadIndex() error {
+	if r.rs == nil {
+		return ErrNotSeekable
+	}
+	if r.indexed || r.header.Version < 2 {
+		r.indexed = true
+		return nil
+	}
+
+	// Reading the index moves the source, come back for Next
+	defer r.restore()()
+
+	end, err := r.end()
+	if err != nil {
+		return err
+	}
+	n := int64(recordHeaderLen(r.header.Flags))
+	if end < HeaderSize+n {
+		r.indexed = true
+		return nil
+	}
+
+	var trailer [footerTrailerSize]byte
+	if end >= HeaderSize+n+footerTrailerSize {
+		if trailer, err = r.readTrailer(); err != nil {
+			return err
+		}
+	}
+	if string(trailer[8:]) != footerMagic {
+		// Not finished, seeks scan from the start and only metadata
+		// written before the first packet is known
+		if r.header.Flags&FlagMetadata != 0 {
+			// A damaged first record only means there is no metadata
+			if kind, _, err := r.readRecordAt(HeaderSize, end); err == nil && kind == recordMetadata {
+				r.metadata = HeaderSize
+			}
+		}
+		r.indexed = true
+		return nil
+	}
+
+	kind, data, err := r.readRecordAt(int64(binary.LittleEndian.Uint64(trailer[:])), end)
+	if err != nil {
+		return err
+	}
+	var f footer
+	if kind != recordFooter {
+		return fmt.Errorf("%w: bad footer offset", ErrInvalidFile)
+	}
+	if err := f.decode(data, r.header.Flags); err != nil {
+		return err
+	}
+
+	// Blocks are linked from the last to the first
+	var blocks [][]indexEntry
+	for at := f.lastIndex; at >= 0; {
+		kind, data, err := r.readRecordAt(at, end)
+		if err != nil {
+			return err
+		}
+		var b indexBlock
+		if kind != recordIndex {
+			return fmt.Errorf("%w: bad index offset %d", ErrInvalidFile, at)
+		}
+		if err := b.decode(data, r.header.Flags); err != nil {
+			return err
+		}
+		if len(blocks) == 0 && len(b.origins) >= len(r.origins) {
+			r.origins = append([]f1telemetry.Origin{{}}, b.origins...)
+		}
+		if b.prev >= at {
+			return fmt.Errorf("%w: index blocks out of order", ErrInvalidFile)
+		}
+		blocks = append(blocks, b.entries)
+		at = b.prev
+	}
+
+	r.index = nil
+	for i := len(blocks) - 1; i >= 0; i-- {
+		r.index = append(r.index, blocks[i]...)
+	}
+	r.footer = &f.Footer
+	r.metadata = f.metadata
+	r.indexed = true
+	return nil
+}
+
+// restore returns a function that moves the source back to where Next
+// continues reading
+func (r *Reader) restore() func() {
+	offset, pending, block, delta, resync := r.offset, r.pending, r.block, r.delta, r.resync
+	return func() {
+		// Next continues where it was, so the delta state stays
+		r.delta = nil
+		err := r.moveTo(offset)
+		r.delta = delta
+		if err == nil {
+			r.pending = pending
+			r.block = block
+			r.resync = resync
+		}
+	}
+}
+
+// end returns the size of the file
+func (r *Reader) end() (int64, error) {
+	end, err := r.rs.Seek(0, io.SeekEnd)
+	return end - r.base, err
+}
+
+// readTrailer returns the last footerTrailerSize bytes of the file, which
+// are the footer offset and footerMagic in finished files
+func (r *Reader) readTrailer() ([footerTrailerSize]byte, error) {
+	var trailer [footerTrailerSize]byte
+	if r.rs == nil {
+		return trailer, ErrNotSeekable
+	}
+	end, err := r.end()
+	if err != nil {
+		return trailer, err
+	}
+	if end < HeaderSize+footerTrailerSize {
+		return trailer, fmt.Errorf("%w: no footer", ErrInvalidFile)
+	}
+	if _, err := r.rs.Seek(r.base+end-footerTrailerSize, io.SeekStart); err != nil {
+		return trailer, err
+	}
+	_, err = io.ReadFull(r.rs, trailer[:])
+	return trailer, err
+}
+
+// readRecordAt returns the kind and data of the record at offset
+func (r *Reader) readRecordAt(offset, end int64) (uint32, []byte, error) {
+	bad := fmt.Errorf("%w: bad record offset %d", ErrInvalidFile, offset)
+	n := recordHeaderLen(r.header.Flags)
+	if offset < HeaderSize || offset > end-int64(n) {
+		return 0, nil, bad
+	}
+	if _, err := r.rs.Seek(r.base+offset, io.SeekStart); err != nil {
+		return 0, nil, err
+	}
+
+	var buf [maxRecordHeaderLen]byte
+	if _, err := io.ReadFull(r.rs, buf[:n]); err != nil {
+		return 0, nil, err
+	}
+	size := binary.LittleEndian.Uint32(buf[8:])
+	length := int64(size &^ recordKinds)
+	if length > maxRecordSize || offset+int64(n)+length > end {
+		return 0, nil, bad
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r.rs, data); err != nil {
+		return 0, nil, err
+	}
+	if n > recordHeaderSize && binary.LittleEndian.Uint32(buf[recordHeaderSize:]) != recordChecksum(buf[:recordHeaderSize], data) {
+		return 0, nil, fmt.Errorf("%w at offset %d", ErrChecksum, offset)
+	}
+	return size & recordKinds, data, nil
+}
+
+// parseOrigin decodes an origin definition record
+func parseOrigin(data []byte) f1telemetry.Origin {
+	endpoint, source, _ := strings.Cut(string(data), "\x00")
+	addr, _ := netip.ParseAddrPort(source)
+	return f1telemetry.Origin{Endpoint: endpoint, Source: addr}
+}
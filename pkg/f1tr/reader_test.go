@@ -0,0 +1,263 @@
+package f1tr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// packetFrame returns the session and frame of a packet, false if it has
+// no valid header
+func packetFrame(data []byte) (uint64, uint32, bool) {
+	var h f1telemetry.PacketHeader
+	if h.UnmarshalBinary(data) != nil {
+		return 0, 0, false
+	}
+	return h.SessionUID, h.OverallFrameIdentifier, true
+}
+
+func TestSeekSessionFrame(t *testing.T) {
+	packets := testPackets(5000)
+	for _, mode := range writerModes {
+		file := writeTestFile(t, mode.opts, packets)
+		for _, session := range testSessions {
+			for _, frame := range []uint32{0, 1, 21, 22, 400, 833, 834, 10000} {
+				t.Run(fmt.Sprint(mode.name, "/", session, "/", frame), func(t *testing.T) {
+					// The first packet of the session at or after frame
+					want := -1
+					for i, p := range packets {
+						if s, f, ok := packetFrame(p.Data); ok && s == session && f >= frame {
+							want = i
+							break
+						}
+					}
+
+					r, err := NewReader(bytes.NewReader(file))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := r.SeekSessionFrame(session, frame); err != nil {
+						t.Fatal(err)
+					}
+					rec, err := r.Next()
+					if want < 0 {
+						if err == nil {
+							t.Fatalf("seek past the session found packet %v", rec.Data[:8])
+						}
+						return
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(rec.Data, packets[want].Data) {
+						t.Fatalf("seek found %v, want packet %d", rec.Timestamp, want)
+					}
+				})
+			}
+		}
+
+		// SeekFrame stays in the first session
+		r, err := NewReader(bytes.NewReader(file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.SeekFrame(500); err != nil {
+			t.Fatal(err)
+		}
+		rec, err := r.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, f, _ := packetFrame(rec.Data); s != testSessions[0] || f != 500 {
+			t.Errorf("%s: SeekFrame found frame %d of session %x", mode.name, f, s)
+		}
+	}
+}
+
+// checkFollowing fails unless Next returns packets from want on
+func checkFollowing(t *testing.T, r *Reader, packets []f1telemetry.RecordedPacket, want int) {
+	t.Helper()
+	for i := want; i < min(want+300, len(packets)); i++ {
+		rec, err := r.Next()
+		if err != nil {
+			t.Fatalf("packet %d after seeking to %d: %v", i, want, err)
+		}
+		if !bytes.Equal(rec.Data, packets[i].Data) || !rec.Timestamp.Equal(packets[i].Timestamp) || rec.Origin != packets[i].Origin {
+			t.Fatalf("packet %d after seeking to %d differs", i, want)
+		}
+	}
+}
+
+func TestSeek(t *testing.T) {
+	packets := testPackets(5000)
+	for _, mode := range writerModes {
+		file := writeTestFile(t, mode.opts, packets)
+
+		// Unfinished files are scanned instead
+		var unfinished bytes.Buffer
+		w, err := NewWriterOptions(&unfinished, mode.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range packets {
+			if err := w.WritePacket(&packets[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for name, file := range map[string][]byte{"finished": file, "unfinished": unfinished.Bytes()} {
+			r, err := NewReader(bytes.NewReader(file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := r.loadIndex(); err != nil {
+				t.Fatal(err)
+			}
+			if name == "finished" && len(r.index) < 3 {
+				t.Fatalf("%s: index of %d entries", mode.name, len(r.index))
+			}
+
+			// Compressed files hold back the packets of the current run
+			// until it ends
+			records, err := readAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			available := packets[:len(records)]
+
+			// The packets around every index entry boundary
+			targets := []int{0, 1, len(available) / 2, len(available) - 1}
+			byTime := make(map[int64]int)
+			for i, p := range available {
+				byTime[p.Timestamp.UnixNano()] = i
+			}
+			for j := 0; j < len(r.index); j += max(len(r.index)/100, 1) {
+				i := byTime[r.index[j].timestamp]
+				targets = append(targets, max(i-1, 0), i, min(i+1, len(available)-1))
+			}
+
+			for _, want := range targets {
+				t.Run(fmt.Sprint(mode.name, "/", name, "/time/", want), func(t *testing.T) {
+					if err := r.SeekTime(packets[want].Timestamp); err != nil {
+						t.Fatal(err)
+					}
+					checkFollowing(t, r, available, want)
+				})
+
+				id := packets[want].Data[min(6, len(packets[want].Data)-1)]
+				t.Run(fmt.Sprint(mode.name, "/", name, "/packet/", want), func(t *testing.T) {
+					if err := r.SeekPacket(id, packets[want].Timestamp); err != nil {
+						t.Fatal(err)
+					}
+					next := want
+					for next < len(available) && (len(available[next].Data) <= 6 || available[next].Data[6] != id) {
+						next++
+					}
+					if next == len(available) {
+						if _, err := r.Next(); err != io.EOF {
+							t.Fatalf("seek past the last packet %d returned %v", id, err)
+						}
+						return
+					}
+					checkFollowing(t, r, available, next)
+				})
+			}
+
+			// Before the first and after the last packet
+			if err := r.SeekTime(packets[0].Timestamp.Add(-time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			checkFollowing(t, r, available, 0)
+			if err := r.SeekTime(available[len(available)-1].Timestamp.Add(time.Nanosecond)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("%s/%s: seek past the end returned %v", mode.name, name, err)
+			}
+		}
+	}
+}
+
+func TestSeekNotSeekable(t *testing.T) {
+	file := writeTestFile(t, WriterOptions{}, testPackets(100))
+	r, err := NewReader(io.MultiReader(bytes.NewReader(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SeekTime(time.Now()); err != ErrNotSeekable {
+		t.Errorf("SeekTime returned %v", err)
+	}
+	if _, err := r.Footer(); err != ErrNotSeekable {
+		t.Errorf("Footer returned %v", err)
+	}
+}
+
+func TestReadOlderVersions(t *testing.T) {
+	packets := testPackets(500)
+
+	// Version 1 as the first recorder wrote it: no flags, and records of
+	// only a timestamp, a size and the packet
+	var v1 bytes.Buffer
+	var header [HeaderSize]byte
+	copy(header[:], Magic)
+	binary.LittleEndian.PutUint16(header[4:], 1)
+	binary.LittleEndian.PutUint64(header[6:], uint64(time.Unix(1700000000, 0).UnixNano()))
+	v1.Write(header[:])
+	v1Packets := make([]f1telemetry.RecordedPacket, len(packets))
+	for i, p := range packets {
+		binary.Write(&v1, binary.LittleEndian, p.Timestamp.UnixNano())
+		binary.Write(&v1, binary.LittleEndian, uint32(len(p.Data)))
+		v1.Write(p.Data)
+		v1Packets[i] = f1telemetry.RecordedPacket{Timestamp: p.Timestamp, Data: p.Data}
+	}
+
+	// Version 2 files are read by their flags
+	v2 := writeTestFile(t, WriterOptions{}, packets)
+	binary.LittleEndian.PutUint16(v2[4:], 2)
+
+	for _, tt := range []struct {
+		name    string
+		file    []byte
+		version uint16
+		packets []f1telemetry.RecordedPacket
+	}{
+		{"version 1", v1.Bytes(), 1, v1Packets},
+		{"version 2", v2, 2, packets},
+	} {
+		r, err := NewReader(bytes.NewReader(tt.file))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if r.Header().Version != tt.version {
+			t.Errorf("%s: read version %d", tt.name, r.Header().Version)
+		}
+		records, err := readAll(r)
+		if err != nil || r.Torn() {
+			t.Fatalf("%s: %v, torn %v", tt.name, err, r.Torn())
+		}
+		if len(records) != len(packets) {
+			t.Fatalf("%s: read %d of %d packets", tt.name, len(records), len(packets))
+		}
+		checkRecords(t, records, tt.packets, false)
+
+		// Seeking scans version 1 files and uses the index of version 2 ones
+		if err := r.SeekTime(packets[300].Timestamp); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		checkFollowing(t, r, tt.packets, 300)
+	}
+}
+
+func TestReadNewerVersion(t *testing.T) {
+	file := writeTestFile(t, WriterOptions{}, testPackets(10))
+	binary.LittleEndian.PutUint16(file[4:], Version+1)
+	if _, err := NewReader(bytes.NewReader(file)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("version %d returned %v", Version+1, err)
+	}
+}
@@ -0,0 +1,190 @@
+package f1tr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// RecoverStats describes what Recover salvaged from a damaged recording
+type RecoverStats struct {
+	Packets uint64 // Packets written to the new recording
+	Damaged int    // Damaged regions skipped
+	Skipped int64  // Bytes in damaged regions
+	Dropped uint64 // Intact delta packets lost with their keyframe
+}
+
+// Recover writes every intact packet of the recording in src to a new
+// recording in dst, stored as set in opts, followed by the last intact
+// metadata. Damaged data is skipped up to the next intact record.
+//
+// Records of files with FlagChecksums must match their checksum; in older
+// files any well-formed record is taken. If opts.Created is zero the
+// creation time of src is kept.
+func Recover(dst io.Writer, src io.Reader, opts WriterOptions) (RecoverStats, error) {
+	br := bufio.NewReaderSize(src, maxRecordSize+maxRecordHeaderLen)
+	header, err := readHeader(br)
+	if err != nil {
+		return RecoverStats{}, err
+	}
+	if opts.Created.IsZero() {
+		opts.Created = header.Created
+	}
+	w, err := NewWriterOptions(dst, opts)
+	if err != nil {
+		return RecoverStats{}, err
+	}
+
+	s := &salvager{
+		w:       w,
+		header:  header,
+		formats: f1telemetry.SupportedFormats(),
+		// The Reader keeps the origins, blocks and delta state
+		r: &Reader{header: header, origins: []f1telemetry.Origin{{}}},
+	}
+	if header.Flags&FlagDelta != 0 {
+		s.r.delta = &deltaState{}
+		s.r.delta.reset()
+	}
+
+	n := recordHeaderLen(header.Flags)
+	damaged := false
+	for {
+		record := intactRecord(br, header.Flags, s.formats)
+		if record == nil {
+			// Skip a byte at a time until a record is intact again
+			if _, err := br.Discard(1); err != nil {
+				break
+			}
+			s.stats.Skipped++
+			damaged = true
+			continue
+		}
+		if damaged {
+			s.damaged()
+			damaged = false
+		}
+
+		if err := s.record(record, n); err != nil {
+			return s.stats, err
+		}
+		br.Discard(len(record))
+	}
+	if damaged {
+		s.damaged()
+	}
+
+	if s.metadata != nil {
+		if err := w.WriteMetadata(s.metadata); err != nil {
+			return s.stats, err
+		}
+	}
+	return s.stats, w.Close()
+}
+
+// salvager holds the state of Recover
+type salvager struct {
+	w        *Writer
+	r        *Reader
+	header   Header
+	formats  []uint16
+	metadata *Metadata
+	stats    RecoverStats
+}
+
+// damaged notes the end of a damaged region. Delta packets after it can
+// not be decoded against the packets before it.
+func (s *salvager) damaged() {
+	s.stats.Damaged++
+	if s.r.delta != nil {
+		s.r.delta.reset()
+	}
+}
+
+// record salvages an intact record
+func (s *salvager) record(record []byte, n int) error {
+	timestamp := int64(binary.LittleEndian.Uint64(record[0:]))
+	size := binary.LittleEndian.Uint32(record[8:])
+	var originID uint16
+	if n >= recordHeaderSize {
+		originID = binary.LittleEndian.Uint16(record[12:])
+	}
+	data := record[n:]
+
+	switch size & recordKinds {
+	case recordIndex:
+		var b indexBlock
+		if b.decode(data, s.header.Flags) == nil {
+			for i, origin := range b.origins {
+				s.origin(uint16(i+1), origin)
+			}
+		}
+	case recordMetadata:
+		m := new(Metadata)
+		if json.Unmarshal(data, m) == nil {
+			s.metadata = m
+		}
+	case recordFooter:
+		// Close writes a new one
+	case recordBlock:
+		if s.r.decompress(data) != nil {
+			s.damaged()
+			return nil
+		}
+		for {
+			timestamp, size, originID, data, rest, ok := splitRecord(s.r.block)
+			if !ok {
+				break
+			}
+			s.r.block = rest
+			if err := s.packet(timestamp, size, originID, data); err != nil {
+				return err
+			}
+		}
+		s.r.block = nil
+	default:
+		return s.packet(timestamp, size, originID, data)
+	}
+	return nil
+}
+
+// packet writes a packet or notes an origin definition
+func (s *salvager) packet(timestamp int64, size uint32, originID uint16, data []byte) error {
+	if size&recordOrigin != 0 {
+		s.origin(originID, parseOrigin(data))
+		return nil
+	}
+
+	if s.r.delta != nil {
+		var err error
+		if data, err = s.r.delta.decode(originID, data); err != nil {
+			s.stats.Dropped++
+			return nil
+		}
+	}
+
+	packet := f1telemetry.RecordedPacket{Timestamp: time.Unix(0, timestamp), Data: data}
+	if int(originID) < len(s.r.origins) {
+		packet.Origin = s.r.origins[originID]
+	}
+	if err := s.w.WritePacket(&packet); err != nil {
+		return err
+	}
+	s.stats.Packets++
+	return nil
+}
+
+// origin defines an origin, leaving unknown ones whose definition was
+// damaged in between
+func (s *salvager) origin(id uint16, origin f1telemetry.Origin) {
+	for int(id) >= len(s.r.origins) {
+		s.r.origins = append(s.r.origins, f1telemetry.Origin{})
+	}
+	if id != 0 && s.r.origins[id] == (f1telemetry.Origin{}) {
+		s.r.origins[id] = origin
+	}
+}
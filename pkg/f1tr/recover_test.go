@@ -0,0 +1,334 @@
+package f1tr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// writerModes are the ways a Writer can store packets
+var writerModes = []struct {
+	name string
+	opts WriterOptions
+}{
+	{"raw", WriterOptions{}},
+	{"compressed", WriterOptions{Level: 6}},
+	{"delta", WriterOptions{Delta: true, KeyframeInterval: 32}},
+	{"delta compressed", WriterOptions{Delta: true, Level: 1}},
+}
+
+// testPackets returns n packets of three types from two senders, each a
+// random change of the last one of its type like real telemetry. The
+// second half is another session, whose frames start again, and some
+// packets, the first one included, are too short to have a header.
+func testPackets(n int) []f1telemetry.RecordedPacket {
+	rng := rand.New(rand.NewSource(1))
+	origins := []f1telemetry.Origin{
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.20:50123")},
+		{Endpoint: "0.0.0.0:20777", Source: netip.MustParseAddrPort("192.168.1.21:50124")},
+	}
+	last := map[uint8][]byte{}
+	packets := make([]f1telemetry.RecordedPacket, n)
+	for i := range packets {
+		id := uint8(i % 3)
+		data := append([]byte(nil), last[id]...)
+		if data == nil {
+			data = make([]byte, 60+int(id)*40)
+			binary.LittleEndian.PutUint16(data, f1telemetry.PacketFormat2025)
+			data[6] = id
+		}
+		session, frame := testSessions[0], i/3
+		if i >= n/2 {
+			session, frame = testSessions[1], (i-n/2)/3
+		}
+		binary.LittleEndian.PutUint64(data[7:], session)
+		binary.LittleEndian.PutUint32(data[23:], uint32(frame))
+		for j := f1telemetry.PacketHeaderSize; j < len(data); j++ {
+			if rng.Intn(8) == 0 {
+				data[j] = byte(rng.Intn(256))
+			}
+		}
+		last[id] = data
+		if i%97 == 0 {
+			data = []byte{byte(i), 1, 2, 3, 4}
+		}
+		packets[i] = f1telemetry.RecordedPacket{
+			Timestamp: time.Unix(1700000000, int64(i)*int64(time.Millisecond)),
+			Data:      data,
+			Origin:    origins[i/7%2],
+		}
+	}
+	return packets
+}
+
+// testSessions are the SessionUIDs of the packets from testPackets
+var testSessions = []uint64{0x1111111111111111, 0x2222222222222222}
+
+// writeTestFile writes packets to a finished recording
+func writeTestFile(t *testing.T, opts WriterOptions, packets []f1telemetry.RecordedPacket) []byte {
+	t.Helper()
+	var file bytes.Buffer
+	w, err := NewWriterOptions(&file, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range packets {
+		if err := w.WritePacket(&packets[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return file.Bytes()
+}
+
+// readAll returns the packets of a recording up to the first error
+func readAll(r *Reader) ([]*Record, error) {
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// checkRecords fails unless records are the packets in order, with gaps
+// allowed if skips is set
+func checkRecords(t *testing.T, records []*Record, packets []f1telemetry.RecordedPacket, skips bool) {
+	t.Helper()
+	j := 0
+	for i, rec := range records {
+		for skips && j < len(packets) && !bytes.Equal(rec.Data, packets[j].Data) {
+			j++
+		}
+		if j == len(packets) {
+			t.Fatalf("record %d is not one of the packets in order", i)
+		}
+		p := &packets[j]
+		if !bytes.Equal(rec.Data, p.Data) || !rec.Timestamp.Equal(p.Timestamp) || rec.Origin != p.Origin {
+			t.Fatalf("record %d differs from packet %d", i, j)
+		}
+		j++
+	}
+}
+
+// recordOffsets returns the offsets of the top-level records in file
+func recordOffsets(t *testing.T, file []byte) []int {
+	t.Helper()
+	h, err := readHeader(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := recordHeaderLen(h.Flags)
+	var offsets []int
+	for offset := HeaderSize; offset < len(file); {
+		offsets = append(offsets, offset)
+		size := binary.LittleEndian.Uint32(file[offset+8:])
+		offset += n + int(size&^recordKinds)
+	}
+	return offsets
+}
+
+// sources returns a seekable and a streaming reader of file
+func sources(file []byte) map[string]io.Reader {
+	return map[string]io.Reader{
+		"seekable":  bytes.NewReader(file),
+		"streaming": io.MultiReader(bytes.NewReader(file)),
+	}
+}
+
+func TestReaderTornTail(t *testing.T) {
+	packets := testPackets(2000)
+	for _, mode := range writerModes {
+		file := writeTestFile(t, mode.opts, packets)
+		offsets := recordOffsets(t, file)
+		last := offsets[len(offsets)/2]
+
+		tails := map[string][]byte{
+			"cut in header": file[:last+5],
+			"cut in data":   file[:last+maxRecordHeaderLen+3],
+			"cut at footer": file[:len(file)-5],
+			"zero filled":   append(append([]byte(nil), file[:last+maxRecordHeaderLen+3]...), make([]byte, 4096)...),
+			"bogus length": func() []byte {
+				torn := append([]byte(nil), file[:last+maxRecordHeaderLen+3]...)
+				binary.LittleEndian.PutUint32(torn[last+8:], maxRecordSize-1)
+				return torn
+			}(),
+		}
+		for name, torn := range tails {
+			for source, src := range sources(torn) {
+				t.Run(fmt.Sprint(mode.name, "/", name, "/", source), func(t *testing.T) {
+					r, err := NewReader(src)
+					if err != nil {
+						t.Fatal(err)
+					}
+					records, err := readAll(r)
+					if err != nil {
+						t.Fatalf("torn tail returned %v", err)
+					}
+					if !r.Torn() {
+						t.Error("Torn is not set")
+					}
+					if len(records) == 0 {
+						t.Error("no packets before the torn tail")
+					}
+					checkRecords(t, records, packets, false)
+				})
+			}
+		}
+	}
+}
+
+func TestReaderDamage(t *testing.T) {
+	packets := testPackets(5000)
+	for _, mode := range writerModes {
+		file := writeTestFile(t, mode.opts, packets)
+		offsets := recordOffsets(t, file)
+
+		damages := map[string]func([]byte){
+			"size of 2nd record": func(b []byte) { b[offsets[1]+9] ^= 0x40 },
+			"huge size":          func(b []byte) { b[offsets[1]+11] ^= 0x07 },
+			"data in the middle": func(b []byte) { b[offsets[len(offsets)/2]+maxRecordHeaderLen] ^= 0xFF },
+			"checksum":           func(b []byte) { b[offsets[len(offsets)/3]+recordHeaderSize] ^= 1 },
+		}
+		for name, damage := range damages {
+			damaged := append([]byte(nil), file...)
+			damage(damaged)
+			for source, src := range sources(damaged) {
+				t.Run(fmt.Sprint(mode.name, "/", name, "/", source), func(t *testing.T) {
+					r, err := NewReader(src)
+					if err != nil {
+						t.Fatal(err)
+					}
+					records, err := readAll(r)
+					if !errors.Is(err, ErrChecksum) {
+						t.Fatalf("damage returned %v after %d packets", err, len(records))
+					}
+
+					// Next continues after the damage
+					rest, err := readAll(r)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if r.Torn() {
+						t.Error("Torn is set")
+					}
+					records = append(records, rest...)
+					if len(records) < len(packets)/2 || len(records) >= len(packets) {
+						t.Errorf("read %d of %d packets", len(records), len(packets))
+					}
+					checkRecords(t, records, packets, true)
+				})
+			}
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	packets := testPackets(3000)
+	for _, mode := range writerModes {
+		var file bytes.Buffer
+		w, err := NewWriterOptions(&file, mode.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteMetadata(&Metadata{Track: "Spa"})
+		for i := range packets {
+			if err := w.WritePacket(&packets[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w.WriteMetadata(&Metadata{Track: "Monza"})
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		offsets := recordOffsets(t, file.Bytes())
+
+		damages := []struct {
+			name     string
+			damage   func([]byte) []byte
+			complete bool // All packets are intact
+		}{
+			{"intact", func(b []byte) []byte { return b }, true},
+			{"no footer", func(b []byte) []byte { return b[:offsets[len(offsets)-1]] }, true},
+			{"torn", func(b []byte) []byte { return b[:len(b)*2/3] }, false},
+			{"size field", func(b []byte) []byte { b[offsets[2]+9] ^= 0x40; return b }, false},
+			{"overwritten", func(b []byte) []byte {
+				copy(b[len(b)/3:], bytes.Repeat([]byte{0xA5}, 300))
+				return b
+			}, false},
+			{"zeroed", func(b []byte) []byte {
+				clear(b[len(b)/2 : len(b)/2+5000])
+				return b
+			}, false},
+		}
+		for _, d := range damages {
+			t.Run(mode.name+"/"+d.name, func(t *testing.T) {
+				damaged := d.damage(append([]byte(nil), file.Bytes()...))
+
+				var out bytes.Buffer
+				created := time.Unix(1700000000, 0)
+				stats, err := Recover(&out, bytes.NewReader(damaged), WriterOptions{Level: 6, Created: created})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				r, err := NewReader(bytes.NewReader(out.Bytes()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				records, err := readAll(r)
+				if err != nil || r.Torn() {
+					t.Fatalf("recovered file: %v, torn %v", err, r.Torn())
+				}
+				if uint64(len(records)) != stats.Packets {
+					t.Errorf("read %d packets, Recover wrote %d", len(records), stats.Packets)
+				}
+				checkRecords(t, records, packets, true)
+				if !r.Header().Created.Equal(created) {
+					t.Errorf("created %v, want %v", r.Header().Created, created)
+				}
+				footer, err := r.Footer()
+				if err != nil || footer == nil || footer.Packets != stats.Packets {
+					t.Errorf("footer %+v, %v", footer, err)
+				}
+
+				if d.complete {
+					if len(records) != len(packets) || stats.Damaged != 0 || stats.Skipped != 0 {
+						t.Errorf("recovered %d of %d packets, %+v", len(records), len(packets), stats)
+					}
+				} else {
+					if stats.Damaged == 0 || len(records) < len(packets)/3 || len(records) >= len(packets) {
+						t.Errorf("recovered %d of %d packets, %+v", len(records), len(packets), stats)
+					}
+				}
+
+				m, err := r.Metadata()
+				if err != nil || m == nil {
+					t.Fatalf("metadata %v, %v", m, err)
+				}
+				want := "Monza"
+				if d.name == "torn" {
+					want = "Spa"
+				}
+				if m.Track != want {
+					t.Errorf("metadata of track %q, want %q", m.Track, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package f1tr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pefman/golang-telemetry-recorder/pkg/f1telemetry"
+)
+
+// recordHeaderSize is the size of a record before its data, without a
+// checksum. Records in compressed blocks have no checksum.
+const recordHeaderSize = 14
+
+// Writer writes packets to a recording. It is not safe for concurrent use.
+type Writer struct {
+	w       io.Writer
+	origins map[f1telemetry.Origin]uint16 // IDs of origins written so far
+	buf     []byte
+	size    int64
+	index   indexBlock // Block being filled, its last entry being the current run
+	run     int        // Packets in the current run
+	footer  footer
+	flags   uint32
+	closed  bool
+	runSize int // Packets per index entry
+
+	// With FlagDelta packets are encoded against the previous one of
+	// their type
+	delta    *deltaState
+	deltaBuf []byte
+
+	// With FlagCompressed the records of the current run are collected
+	// in block and written compressed when the run ends
+	block      []byte
+	compressed bytes.Buffer
+	zw         *gzip.Writer
+}
+
+// WriterOptions selects how a Writer stores packets
+type WriterOptions struct {
+	// Level compresses the packets, from gzip.BestSpeed to
+	// gzip.BestCompression. Level 0 writes them uncompressed.
+	Level int
+
+	// Delta stores most packets as the bytes that changed since the
+	// previous packet of the same type and origin. Every KeyframeInterval
+	// packets, DefaultKeyframeInterval if 0, the next packet of each type
+	// is stored whole so readers can seek.
+	Delta            bool
+	KeyframeInterval int
+
+	// Created is the creation time in the file header, the current time
+	// if zero
+	Created time.Time
+}
+
+// NewWriter writes a file header to w and returns a Writer for the packets
+func NewWriter(w io.Writer) (*Writer, error) {
+	return NewWriterOptions(w, WriterOptions{})
+}
+
+// NewWriterLevel is like NewWriter but compresses the packets with the
+// given level, from gzip.BestSpeed to gzip.BestCompression. Level 0
+// writes them uncompressed.
+func NewWriterLevel(w io.Writer, level int) (*Writer, error) {
+	return NewWriterOptions(w, WriterOptions{Level: level})
+}
+
+// NewWriterOptions is like NewWriter but stores packets as set in opts
+func NewWriterOptions(w io.Writer, opts WriterOptions) (*Writer, error) {
+	writer := &Writer{
+		w:       w,
+		origins: map[f1telemetry.Origin]uint16{{}: 0},
+		size:    HeaderSize,
+		index:   indexBlock{prev: -1},
+		footer:  footer{lastIndex: -1, metadata: -1},
+		flags:   FlagOrigins | FlagMetadata | FlagChecksums | FlagSessions,
+		runSize: indexEntryPackets,
+	}
+
+	if opts.Level != 0 {
+		if opts.Level < gzip.BestSpeed || opts.Level > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid compression level: %d", opts.Level)
+		}
+		writer.flags |= FlagCompressed
+		writer.zw, _ = gzip.NewWriterLevel(io.Discard, opts.Level)
+	}
+
+	// Keyframes start index entries, so readers can start decoding at any
+	if opts.Delta {
+		if opts.KeyframeInterval < 0 {
+			return nil, fmt.Errorf("invalid keyframe interval: %d", opts.KeyframeInterval)
+		}
+		writer.flags |= FlagDelta
+		writer.delta = &deltaState{}
+		writer.runSize = opts.KeyframeInterval
+		if writer.runSize == 0 {
+			writer.runSize = DefaultKeyframeInterval
+		}
+	}
+
+	header := Header{Version: Version, Created: opts.Created, Flags: writer.flags}
+	if header.Created.IsZero() {
+		header.Created = time.Now()
+	}
+	if err := writeHeader(w, header); err != nil {
+		return nil, fmt.Errorf("failed to write file header: %w", err)
+	}
+	return writer, nil
+}
+
+// WritePacket appends a packet with its timestamp and origin
+func (w *Writer) WritePacket(packet *f1telemetry.RecordedPacket) error {
+	if w.closed {
+		return ErrClosed
+	}
+	timestamp := packet.Timestamp.UnixNano()
+
+	// Define the origin before its first packet
+	origin, ok := w.origins[packet.Origin]
+	if !ok && len(w.origins) <= 0xFFFF {
+		origin = uint16(len(w.origins))
+		definition := encodeOrigin(packet.Origin)
+		if err := w.writePacketRecord(timestamp, uint32(len(definition))|recordOrigin, origin, definition); err != nil {
+			return fmt.Errorf("failed to write origin: %w", err)
+		}
+		w.origins[packet.Origin] = origin
+		w.index.origins = append(w.index.origins, packet.Origin)
+	}
+
+	// Headers are decoded from the data, packet.Header may not be set
+	var header f1telemetry.PacketHeader
+	valid := header.UnmarshalBinary(packet.Data) == nil
+
+	// Frames restart with a session, so a new one starts a new entry
+	if valid && w.run > 0 {
+		if e := &w.index.entries[len(w.index.entries)-1]; e.types != 0 && e.session != header.SessionUID {
+			if err := w.endRun(w.footer.End.UnixNano()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if w.run == 0 {
+		w.index.entries = append(w.index.entries, indexEntry{
+			offset:    w.size,
+			timestamp: timestamp,
+		})
+		if w.delta != nil {
+			w.delta.reset()
+		}
+	}
+	if e := &w.index.entries[len(w.index.entries)-1]; valid && e.types == 0 {
+		e.frame = header.OverallFrameIdentifier
+		e.session = header.SessionUID
+	}
+
+	data := packet.Data
+	if w.delta != nil {
+		w.deltaBuf = w.delta.encode(w.deltaBuf[:0], origin, data)
+		data = w.deltaBuf
+	}
+	if err := w.writePacketRecord(timestamp, uint32(len(data)), origin, data); err != nil {
+		return fmt.Errorf("failed to write packet: %w", err)
+	}
+
+	w.count(timestamp, header.PacketID, valid)
+	if w.run++; w.run == w.runSize {
+		return w.endRun(timestamp)
+	}
+	return nil
+}
+
+// Close writes the remaining index entries and the footer. It does not
+// close the underlying writer. Packets can not be written after Close.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	timestamp := w.footer.End.UnixNano()
+	if w.run > 0 {
+		if err := w.endRun(timestamp); err != nil {
+			return err
+		}
+	}
+	if len(w.index.entries) > 0 {
+		if err := w.writeIndex(timestamp); err != nil {
+			return err
+		}
+	}
+
+	w.footer.lastIndex = w.index.prev
+	data := w.footer.encode(w.size, w.flags)
+	if err := w.writeRecord(timestamp, uint32(len(data))|recordFooter, 0, data); err != nil {
+		return fmt.Errorf("failed to write footer: %w", err)
+	}
+	return nil
+}
+
+// Size returns the number of bytes written so far, header included.
+// Compressed packets are counted once their block is written.
+func (w *Writer) Size() int64 {
+	return w.size
+}
+
+// endRun finishes the current index entry, writing its block if packets
+// are compressed and the index block once it is full
+func (w *Writer) endRun(timestamp int64) error {
+	w.run = 0
+	if w.flags&FlagCompressed != 0 {
+		if err := w.writeBlock(); err != nil {
+			return err
+		}
+	}
+	if len(w.index.entries) == indexBlockEntries {
+		return w.writeIndex(timestamp)
+	}
+	return nil
+}
+
+// writeBlock compresses the records of the current run into a
+// recordBlock record. Every block is a complete gzip stream, so readers
+// can start at any of them.
+func (w *Writer) writeBlock() error {
+	if len(w.block) == 0 {
+		return nil
+	}
+	w.compressed.Reset()
+	w.zw.Reset(&w.compressed)
+	if _, err := w.zw.Write(w.block); err != nil {
+		return fmt.Errorf("failed to compress block: %w", err)
+	}
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress block: %w", err)
+	}
+	w.block = w.block[:0]
+
+	// The block starts the current index entry
+	timestamp := w.index.entries[len(w.index.entries)-1].timestamp
+	data := w.compressed.Bytes()
+	if err := w.writeRecord(timestamp, uint32(len(data))|recordBlock, 0, data); err != nil {
+		return fmt.Errorf("failed to write block: %w", err)
+	}
+	return nil
+}
+
+// count adds a written packet to the totals of the footer and the current run
+func (w *Writer) count(timestamp int64, id uint8, valid bool) {
+	if w.footer.Packets == 0 {
+		w.footer.Start = time.Unix(0, timestamp)
+	}
+	w.footer.Packets++
+	w.footer.End = time.Unix(0, timestamp)
+	if !valid {
+		return
+	}
+
+	if int(id) >= len(w.footer.PacketsByType) {
+		w.footer.PacketsByType = append(w.footer.PacketsByType, make([]uint64, int(id)+1-len(w.footer.PacketsByType))...)
+	}
+	w.footer.PacketsByType[id]++
+	if id < 32 {
+		w.index.entries[len(w.index.entries)-1].types |= 1 << id
+	}
+}
+
+// writeIndex writes the index block and starts the next one
+func (w *Writer) writeIndex(timestamp int64) error {
+	offset := w.size
+	data := w.index.encode(w.flags)
+	if err := w.writeRecord(timestamp, uint32(len(data))|recordIndex, 0, data); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	w.index.prev = offset
+	w.index.entries = w.index.entries[:0]
+	return nil
+}
+
+// writePacketRecord writes a packet or origin record, or adds it to the
+// current block if packets are compressed
+func (w *Writer) writePacketRecord(timestamp int64, size uint32, origin uint16, data []byte) error {
+	if w.flags&FlagCompressed == 0 {
+		return w.writeRecord(timestamp, size, origin, data)
+	}
+	w.block = binary.LittleEndian.AppendUint64(w.block, uint64(timestamp))
+	w.block = binary.LittleEndian.AppendUint32(w.block, size)
+	w.block = binary.LittleEndian.AppendUint16(w.block, origin)
+	w.block = append(w.block, data...)
+	return nil
+}
+
+// writeRecord writes one record in a single Write
+func (w *Writer) writeRecord(timestamp int64, size uint32, origin uint16, data []byte) error {
+	if len(data) > maxRecordSize {
+		return fmt.Errorf("record of %d bytes is larger than %d", len(data), maxRecordSize)
+	}
+	header := recordHeaderLen(w.flags)
+	n := header + len(data)
+	if cap(w.buf) < n {
+		w.buf = make([]byte, n)
+	}
+	buf := w.buf[:n]
+	binary.LittleEndian.PutUint64(buf[0:], uint64(timestamp))
+	binary.LittleEndian.PutUint32(buf[8:], size)
+	binary.LittleEndian.PutUint16(buf[12:], origin)
+	copy(buf[header:], data)
+	if w.flags&FlagChecksums != 0 {
+		binary.LittleEndian.PutUint32(buf[recordHeaderSize:], recordChecksum(buf[:recordHeaderSize], data))
+	}
+
+	written, err := w.w.Write(buf)
+	w.size += int64(written)
+	return err
+}
@@ -0,0 +1,180 @@
+package f1tr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// roundTripModes add the other compression levels and keyframe
+// intervals to writerModes
+var roundTripModes = append(writerModes[:len(writerModes):len(writerModes)], []struct {
+	name string
+	opts WriterOptions
+}{
+	{"fastest", WriterOptions{Level: 1}},
+	{"smallest", WriterOptions{Level: 9}},
+	{"delta default keyframes", WriterOptions{Delta: true}},
+	{"delta keyframes 1", WriterOptions{Delta: true, KeyframeInterval: 1}},
+	{"delta compressed keyframes 1000", WriterOptions{Delta: true, Level: 9, KeyframeInterval: 1000}},
+}...)
+
+func TestRoundTrip(t *testing.T) {
+	packets := testPackets(3000)
+
+	// Footer totals count packets with a valid header by type
+	var byType []uint64
+	for _, p := range packets {
+		if _, _, ok := packetFrame(p.Data); ok {
+			for int(p.Data[6]) >= len(byType) {
+				byType = append(byType, 0)
+			}
+			byType[p.Data[6]]++
+		}
+	}
+
+	for _, mode := range roundTripModes {
+		t.Run(mode.name, func(t *testing.T) {
+			var file bytes.Buffer
+			opts := mode.opts
+			opts.Created = time.Unix(1700000000, 42)
+			w, err := NewWriterOptions(&file, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteMetadata(&Metadata{Track: "Spa", Tags: []string{"league"}}); err != nil {
+				t.Fatal(err)
+			}
+			for i := range packets {
+				if err := w.WritePacket(&packets[i]); err != nil {
+					t.Fatal(err)
+				}
+				// Metadata in the middle of a run
+				if i == 100 || i == 2000 {
+					if err := w.WriteMetadata(&Metadata{Track: fmt.Sprint("Spa ", i)}); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			unfinished := append([]byte(nil), file.Bytes()...)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if w.Size() != int64(file.Len()) {
+				t.Errorf("Size %d, wrote %d bytes", w.Size(), file.Len())
+			}
+			if err := w.WritePacket(&packets[0]); err != ErrClosed {
+				t.Errorf("WritePacket after Close returned %v", err)
+			}
+
+			for source, src := range sources(file.Bytes()) {
+				r, err := NewReader(src)
+				if err != nil {
+					t.Fatal(err)
+				}
+				h := r.Header()
+				if h.Version != Version || !h.Created.Equal(opts.Created) {
+					t.Errorf("%s: header %+v", source, h)
+				}
+				if got := h.Flags&FlagCompressed != 0; got != (opts.Level != 0) {
+					t.Errorf("%s: compressed flag %v", source, got)
+				}
+				if got := h.Flags&FlagDelta != 0; got != opts.Delta {
+					t.Errorf("%s: delta flag %v", source, got)
+				}
+
+				records, err := readAll(r)
+				if err != nil || r.Torn() {
+					t.Fatalf("%s: %v, torn %v", source, err, r.Torn())
+				}
+				if len(records) != len(packets) {
+					t.Fatalf("%s: read %d of %d packets", source, len(records), len(packets))
+				}
+				checkRecords(t, records, packets, false)
+			}
+
+			r, err := NewReader(bytes.NewReader(file.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			footer, err := r.Footer()
+			if err != nil || footer == nil {
+				t.Fatalf("footer %v, %v", footer, err)
+			}
+			if footer.Packets != uint64(len(packets)) || !footer.Start.Equal(packets[0].Timestamp) || !footer.End.Equal(packets[len(packets)-1].Timestamp) {
+				t.Errorf("footer %+v", footer)
+			}
+			if fmt.Sprint(footer.PacketsByType) != fmt.Sprint(byType) {
+				t.Errorf("packets by type %v, want %v", footer.PacketsByType, byType)
+			}
+			if m, err := r.Metadata(); err != nil || m == nil || m.Track != "Spa 2000" {
+				t.Errorf("metadata %+v, %v", m, err)
+			}
+
+			// Unfinished files have all packets written so far and the
+			// metadata written before the first one
+			r, err = NewReader(bytes.NewReader(unfinished))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if footer, err := r.Footer(); err != nil || footer != nil {
+				t.Errorf("unfinished footer %+v, %v", footer, err)
+			}
+			if m, err := r.Metadata(); err != nil || m == nil || m.Track != "Spa" || len(m.Tags) != 1 {
+				t.Errorf("unfinished metadata %+v, %v", m, err)
+			}
+			records, err := readAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRecords(t, records, packets, false)
+			if len(records) < len(packets)-1000 {
+				t.Errorf("unfinished file has %d packets", len(records))
+			}
+		})
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	for _, opts := range []WriterOptions{
+		{Level: -2},
+		{Level: 10},
+		{Delta: true, KeyframeInterval: -1},
+	} {
+		if _, err := NewWriterOptions(io.Discard, opts); err == nil {
+			t.Errorf("%+v accepted", opts)
+		}
+	}
+}
+
+func TestEmptyRecording(t *testing.T) {
+	for _, mode := range roundTripModes {
+		var file bytes.Buffer
+		w, err := NewWriterOptions(&file, mode.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := NewReader(bytes.NewReader(file.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec, err := r.Next(); err != io.EOF {
+			t.Errorf("%s: Next returned %v, %v", mode.name, rec, err)
+		}
+		if err := r.SeekTime(time.Unix(1700000000, 0)); err != nil {
+			t.Errorf("%s: SeekTime returned %v", mode.name, err)
+		}
+		if footer, err := r.Footer(); err != nil || footer == nil || footer.Packets != 0 {
+			t.Errorf("%s: footer %+v, %v", mode.name, footer, err)
+		}
+		if m, err := r.Metadata(); err != nil || m != nil {
+			t.Errorf("%s: metadata %+v, %v", mode.name, m, err)
+		}
+	}
+}